@@ -0,0 +1,49 @@
+package promptkit
+
+import (
+	"sync"
+
+	"github.com/muesli/termenv"
+)
+
+var (
+	colorProfileMu     sync.RWMutex
+	forcedColorProfile *termenv.Profile
+)
+
+// SetColorProfile forces the color profile of all prompts regardless of their
+// ColorProfile, for example termenv.TrueColor to keep colors in a recorded log
+// when the output is piped or termenv.Ascii to strip them for clean diffs. It
+// applies to the templates and default styles of prompts that are initialized
+// afterwards, so it should be called at program start. The forced profile also
+// takes precedence over an explicitly configured ColorProfile, so prompts that
+// need their own profile require ResetColorProfile to be called first.
+func SetColorProfile(p termenv.Profile) {
+	colorProfileMu.Lock()
+	defer colorProfileMu.Unlock()
+
+	forcedColorProfile = &p
+}
+
+// ResetColorProfile removes the color profile that was forced with
+// SetColorProfile such that the ColorProfile of each prompt applies again.
+func ResetColorProfile() {
+	colorProfileMu.Lock()
+	defer colorProfileMu.Unlock()
+
+	forcedColorProfile = nil
+}
+
+// ResolveColorProfile returns the color profile that was forced with
+// SetColorProfile or the configured profile otherwise. It is used by the
+// prompts to determine the profile of their templates.
+func ResolveColorProfile(configured termenv.Profile) termenv.Profile {
+	colorProfileMu.RLock()
+	defer colorProfileMu.RUnlock()
+
+	if forcedColorProfile != nil {
+		return *forcedColorProfile
+	}
+
+	return configured
+}
@@ -0,0 +1,44 @@
+package confirmation
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// auditEntry is the structure of a line that is written to the AuditWriter.
+type auditEntry struct {
+	Time        string `json:"time"`
+	Prompt      string `json:"prompt"`
+	Answer      string `json:"answer"`
+	UsedDefault bool   `json:"usedDefault"`
+	Context     any    `json:"context,omitempty"`
+}
+
+// writeAuditEntry appends a JSON line describing the concluded prompt to the
+// AuditWriter if it is configured.
+func (m *Model) writeAuditEntry() error {
+	if m.AuditWriter == nil {
+		return nil
+	}
+
+	entry := auditEntry{
+		Time:        time.Now().Format(time.RFC3339),
+		Prompt:      m.Prompt,
+		Answer:      stateFromValue(m.value),
+		UsedDefault: m.usedDefault(),
+		Context:     m.Context,
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal audit entry: %w", err)
+	}
+
+	_, err = m.AuditWriter.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("write audit entry: %w", err)
+	}
+
+	return nil
+}
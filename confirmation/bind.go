@@ -0,0 +1,35 @@
+package confirmation
+
+// Bind binds the confirmation to a bool such as the target of a command line
+// flag for use with RunBound. If explicitlySet is true, for example because
+// the flag was passed on the command line, RunBound does not prompt and
+// returns the bound value instead. Otherwise, RunBound prompts and writes the
+// result back into target. Whether a flag of a flag.FlagSet was explicitly set
+// can be determined with flag.FlagSet.Visit. Binding a nil pointer removes
+// the binding.
+func (c *Confirmation) Bind(target *bool, explicitlySet bool) {
+	c.boundValue = target
+	c.boundExplicitly = explicitlySet
+}
+
+// RunBound executes the confirmation prompt while honoring the binding that
+// was configured with Bind. If no binding is configured, it is equivalent to
+// RunPrompt.
+func (c *Confirmation) RunBound() (bool, error) {
+	if c.boundValue == nil {
+		return c.RunPrompt()
+	}
+
+	if c.boundExplicitly {
+		return *c.boundValue, nil
+	}
+
+	value, err := c.RunPrompt()
+	if err != nil {
+		return false, err
+	}
+
+	*c.boundValue = value
+
+	return value, nil
+}
@@ -0,0 +1,245 @@
+package confirmation
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit"
+	"github.com/muesli/termenv"
+)
+
+const (
+	// DefaultChoiceTemplate defines the default appearance of the choice
+	// prompt and can be copied as a starting point for a custom template.
+	DefaultChoiceTemplate = `
+{{- if .Icon }}{{ print .Icon " " }}{{ end -}}
+{{- Bold (ThemePrompt .Prompt) -}}
+{{- range $i, $action := .Actions }}
+	{{- if eq $.SelectedIndex $i -}}
+		{{- print " " (Bold (ThemeSelected (print "▸" $action))) -}}
+	{{- else -}}
+		{{- print "  " (ThemeUnselected $action) -}}
+	{{- end -}}
+{{- end -}}
+`
+
+	// DefaultChoiceResultTemplate defines the default appearance with which
+	// the final result of the choice prompt is presented.
+	DefaultChoiceResultTemplate = `
+{{- if .ResultIcon }}{{ print .ResultIcon " " }}{{ end -}}
+{{- print .Prompt " " (Foreground .FinalColor .FinalAction) "\n" -}}
+`
+)
+
+// Choice represents a configurable prompt that is similar to a confirmation
+// but offers more than two named actions such as Overwrite, Skip and Cancel.
+// The actions are displayed inline and are navigated horizontally.
+type Choice struct {
+	// Prompt holds the question.
+	Prompt string
+
+	// Icon is rendered in front of the prompt by the built-in templates, for
+	// example promptkit.DefaultIcons.Question. ResultIcon is rendered in front
+	// of the prompt by the built-in result templates, for example
+	// promptkit.DefaultIcons.Success. By default, no icons are rendered.
+	Icon       string
+	ResultIcon string
+
+	// Actions holds the labels of the actions the user can choose from.
+	Actions []string
+
+	// DefaultIndex is the index of the action that is selected at startup.
+	DefaultIndex int
+
+	// LoopCursor enables the cursor to loop around to the first action when
+	// navigating past the last action and the other way around.
+	LoopCursor bool
+
+	// Template holds the display template. A custom template can be used to
+	// completely customize the appearance of the choice prompt. If empty, the
+	// DefaultChoiceTemplate is used. The following variables and functions are
+	// available:
+	//
+	//  * Prompt string: The configured prompt.
+	//  * Icon string: The configured Icon.
+	//  * Actions []string: The configured actions.
+	//  * SelectedIndex int: The index of the currently selected action.
+	//  * DefaultIndex int: The index of the default action.
+	//  * TerminalWidth int: The width of the terminal.
+	//  * promptkit.UtilFuncMap: Handy helper functions.
+	//  * termenv TemplateFuncs (see https://github.com/muesli/termenv).
+	//  * The functions of the configured Theme, see
+	//    promptkit.Theme.TemplateFuncs.
+	//  * The functions specified in ExtendedTemplateFuncs.
+	Template string
+
+	// ResultTemplate is rendered as soon as an action has been chosen. It is
+	// intended to permanently indicate the result of the prompt when the input
+	// itself has disappeared. This template is only rendered in the Run()
+	// method and NOT when the choice prompt is used as a model. The following
+	// variables and functions are available:
+	//
+	//  * FinalAction string: The label of the chosen action.
+	//  * FinalIndex int: The index of the chosen action.
+	//  * FinalColor string: The SelectedColor of the Theme or DefaultYesColor
+	//    if it is empty.
+	//  * Prompt string: The configured prompt.
+	//  * ResultIcon string: The configured ResultIcon.
+	//  * Actions []string: The configured actions.
+	//  * TerminalWidth int: The width of the terminal.
+	//  * promptkit.UtilFuncMap: Handy helper functions.
+	//  * termenv TemplateFuncs (see https://github.com/muesli/termenv).
+	//  * The functions of the configured Theme, see
+	//    promptkit.Theme.TemplateFuncs.
+	//  * The functions specified in ExtendedTemplateFuncs.
+	ResultTemplate string
+
+	// ExtendedTemplateFuncs can be used to add additional functions to the
+	// evaluation scope of the templates.
+	ExtendedTemplateFuncs template.FuncMap
+
+	// Theme colors the prompt, the selected action, the other actions and the
+	// final action in the built-in templates like Confirmation.Theme.
+	Theme promptkit.Theme
+
+	// KeyMap determines with which keys the choice prompt is controlled. By
+	// default, DefaultChoiceKeyMap is used.
+	KeyMap *ChoiceKeyMap
+
+	// WrapMode decides which way the prompt view is wrapped if it does not fit
+	// the terminal. It can be a WrapMode provided by promptkit or a custom
+	// function. By default it is promptkit.PrefixHangingIndent such that wrapped
+	// lines stay aligned under the icon. It can also be nil which disables
+	// wrapping and likely causes output glitches.
+	WrapMode promptkit.WrapMode
+
+	// TrimBlankLines removes leading and trailing blank lines from the rendered
+	// view such that templates with surrounding whitespace do not produce stray
+	// empty lines. By default, the view is rendered unmodified.
+	TrimBlankLines bool
+
+	// AltScreen renders the prompt in the alternate screen buffer such that it
+	// does not scroll the terminal's scrollback. The result is printed to the
+	// normal screen after the prompt has concluded.
+	AltScreen bool
+
+	// RecordKeys collects all key presses that are processed by the choice
+	// prompt if it is not nil. The recorded keys can be replayed with
+	// promptkit.ReplayKeys, for example to reproduce a reported bug.
+	RecordKeys *[]tea.KeyMsg
+
+	// InitialWidth is the terminal width that is assumed until the terminal
+	// reports its actual size. Together with Input, Output and ColorProfile,
+	// it allows serving the prompt over connections where the terminal cannot be
+	// detected, such as SSH sessions. If it is 0, the width is unknown until
+	// the first tea.WindowSizeMsg.
+	InitialWidth int
+
+	// Managed disables everything the prompt does to the terminal or the program
+	// beyond rendering its view such that it can be embedded in a parent bubbletea
+	// program that owns the terminal. When set, the model does not return tea.Quit
+	// when the prompt concludes or aborts but a command that emits a
+	// ChoiceDoneMsg with the result such that the parent program can decide
+	// what to do next. The model stops updating afterwards and RunPrompt should
+	// not be used.
+	Managed bool
+
+	// ForwardUnhandledKeys makes the model emit a promptkit.UnhandledKeyMsg for
+	// key presses that match none of the bindings of the KeyMap like
+	// Confirmation.ForwardUnhandledKeys.
+	ForwardUnhandledKeys bool
+
+	// LineEnding is used for the line breaks of the output that is written
+	// outside of the bubbletea renderer, which is the result that is printed
+	// after the AltScreen was left. Raw terminals that do not translate line
+	// feeds print such output like a staircase, which promptkit.CRLF avoids. By
+	// default, "\n" is used. The views rendered while the prompt is running
+	// always end their lines with "\r\n".
+	LineEnding string
+
+	// ProgramOptions are passed to tea.NewProgram when RunPrompt starts the
+	// interactive prompt, for example tea.WithoutSignalHandler. They are applied
+	// after the built-in options for Input, Output and AltScreen such that they
+	// can override them.
+	ProgramOptions []tea.ProgramOption
+
+	// Output is the output writer, by default os.Stdout is used.
+	Output io.Writer
+	// Input is the input reader, by default, os.Stdin is used.
+	Input io.Reader
+
+	// ColorProfile determines how colors are rendered. By default, the terminal
+	// is queried. A profile that is forced with promptkit.SetColorProfile takes
+	// precedence and a single prompt cannot opt out of it.
+	ColorProfile termenv.Profile
+}
+
+// NewChoice creates a new choice prompt with the given actions where the first
+// action is selected by default. See the Choice properties for more
+// documentation.
+func NewChoice(prompt string, actions ...string) *Choice {
+	return &Choice{
+		Prompt:                prompt,
+		Actions:               actions,
+		Template:              DefaultChoiceTemplate,
+		ResultTemplate:        DefaultChoiceResultTemplate,
+		KeyMap:                NewDefaultChoiceKeyMap(),
+		ExtendedTemplateFuncs: template.FuncMap{},
+		WrapMode:              promptkit.PrefixHangingIndent,
+		Output:                os.Stdout,
+		Input:                 os.Stdin,
+	}
+}
+
+// ValidateConfig checks the actions, the key map and the templates of the
+// choice prompt without running it.
+func (c *Choice) ValidateConfig() error {
+	if len(c.Actions) == 0 {
+		return fmt.Errorf("no actions provided")
+	}
+
+	if c.DefaultIndex < 0 || c.DefaultIndex >= len(c.Actions) {
+		return fmt.Errorf("default index %d out of bounds", c.DefaultIndex)
+	}
+
+	err := validateChoiceKeyMap(c.KeyMap)
+	if err != nil {
+		return fmt.Errorf("insufficient key map: %w", err)
+	}
+
+	m := NewChoiceModel(c)
+
+	_, err = m.initTemplate()
+	if err != nil {
+		return err
+	}
+
+	_, err = m.initResultTemplate()
+
+	return err
+}
+
+var _ promptkit.ConfigValidator = &Choice{}
+
+// RunPrompt executes the choice prompt and returns the label of the chosen
+// action.
+func (c *Choice) RunPrompt() (string, error) {
+	err := validateChoiceKeyMap(c.KeyMap)
+	if err != nil {
+		return "", fmt.Errorf("insufficient key map: %w", err)
+	}
+
+	m := NewChoiceModel(c)
+
+	err = promptkit.Run(m, promptkit.WithOutput(c.Output), promptkit.WithInput(c.Input),
+		promptkit.WithAltScreen(c.AltScreen),
+		promptkit.WithLineEnding(c.LineEnding), promptkit.WithProgramOptions(c.ProgramOptions...))
+	if err != nil {
+		return "", err
+	}
+
+	return m.Value()
+}
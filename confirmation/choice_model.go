@@ -0,0 +1,295 @@
+package confirmation
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/internal/keys"
+	"github.com/muesli/termenv"
+)
+
+// ChoiceDoneMsg is emitted by a Managed ChoiceModel when the prompt concludes
+// or aborts instead of quitting the program. Value and Err correspond to the
+// result of ChoiceModel.Value. Afterwards, the model ignores all messages.
+type ChoiceDoneMsg struct {
+	Value string
+	Err   error
+}
+
+// ChoiceModel implements the bubbletea.Model for a choice prompt.
+type ChoiceModel struct {
+	*Choice
+
+	// Err holds errors that may occur during the execution of
+	// the choice prompt.
+	Err error
+
+	// MaxWidth limits the width of the view using the Choice's WrapMode.
+	MaxWidth int
+
+	tmpl       *template.Template
+	resultTmpl *template.Template
+
+	currentIdx int
+
+	quitting bool
+
+	width int
+}
+
+// ensure that the Model interface is implemented.
+var _ tea.Model = &ChoiceModel{}
+
+// NewChoiceModel returns a new model based on the provided choice prompt.
+func NewChoiceModel(choice *Choice) *ChoiceModel {
+	return &ChoiceModel{
+		Choice:     choice,
+		currentIdx: choice.DefaultIndex,
+	}
+}
+
+// Init initializes the choice prompt model.
+func (m *ChoiceModel) Init() tea.Cmd {
+	m.width = zeroAwareMin(m.InitialWidth, m.MaxWidth)
+
+	if len(m.Actions) == 0 {
+		m.Err = fmt.Errorf("no actions provided")
+
+		return m.quit()
+	}
+
+	if m.currentIdx < 0 || m.currentIdx >= len(m.Actions) {
+		m.Err = fmt.Errorf("default index %d out of bounds", m.currentIdx)
+
+		return m.quit()
+	}
+
+	m.tmpl, m.Err = m.initTemplate()
+	if m.Err != nil {
+		return m.quit()
+	}
+
+	m.resultTmpl, m.Err = m.initResultTemplate()
+	if m.Err != nil {
+		return m.quit()
+	}
+
+	return nil
+}
+
+func (m *ChoiceModel) initTemplate() (*template.Template, error) {
+	return promptkit.ParseTemplate("view", m.Template,
+		termenv.TemplateFuncs(m.colorProfile()),
+		promptkit.UtilFuncMap(),
+		m.Theme.TemplateFuncs(m.colorProfile()),
+		m.ExtendedTemplateFuncs,
+	)
+}
+
+func (m *ChoiceModel) initResultTemplate() (*template.Template, error) {
+	if m.ResultTemplate == "" {
+		return nil, nil
+	}
+
+	return promptkit.ParseTemplate("result", m.ResultTemplate,
+		termenv.TemplateFuncs(m.colorProfile()),
+		promptkit.UtilFuncMap(),
+		m.Theme.TemplateFuncs(m.colorProfile()),
+		m.ExtendedTemplateFuncs,
+	)
+}
+
+// colorProfile returns the configured ColorProfile unless another profile was
+// forced with promptkit.SetColorProfile.
+func (m *ChoiceModel) colorProfile() termenv.Profile {
+	return promptkit.ResolveColorProfile(m.ColorProfile)
+}
+
+// Update updates the model based on the received message.
+func (m *ChoiceModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.Managed && m.quitting {
+		// the parent program was already notified with a ChoiceDoneMsg
+		return m, nil
+	}
+
+	if m.Err != nil {
+		return m, m.quit()
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		keys.Record(m.RecordKeys, msg)
+
+		switch {
+		case keyMatches(msg, m.KeyMap.Submit):
+			m.quitting = true
+
+			return m, m.quit()
+		case keyMatches(msg, m.KeyMap.Interrupt):
+			m.Err = promptkit.ErrInterrupted
+			m.quitting = true
+
+			return m, m.quit()
+		case keyMatches(msg, m.KeyMap.Abort):
+			m.Err = promptkit.ErrAborted
+			m.quitting = true
+
+			return m, m.quit()
+		case keyMatches(msg, m.KeyMap.Previous):
+			m.cursorPrevious()
+		case keyMatches(msg, m.KeyMap.Next):
+			m.cursorNext()
+		case m.ForwardUnhandledKeys:
+			return m, promptkit.ForwardKey(msg)
+		default: // do nothing
+		}
+	case tea.WindowSizeMsg:
+		m.width = zeroAwareMin(msg.Width, m.MaxWidth)
+	case error:
+		m.Err = msg
+
+		return m, m.quit()
+	}
+
+	return m, nil
+}
+
+func (m *ChoiceModel) cursorPrevious() {
+	switch {
+	case m.currentIdx > 0:
+		m.currentIdx--
+	case m.LoopCursor:
+		m.currentIdx = len(m.Actions) - 1
+	}
+}
+
+func (m *ChoiceModel) cursorNext() {
+	switch {
+	case m.currentIdx < len(m.Actions)-1:
+		m.currentIdx++
+	case m.LoopCursor:
+		m.currentIdx = 0
+	}
+}
+
+// View renders the choice prompt.
+func (m *ChoiceModel) View() string {
+	if m.quitting {
+		view, err := m.resultView()
+		if err != nil {
+			m.Err = err
+
+			return ""
+		}
+
+		return m.wrap(view)
+	}
+
+	// avoid panics if Quit is sent during Init
+	if m.tmpl == nil {
+		return ""
+	}
+
+	viewBuffer := &bytes.Buffer{}
+
+	err := m.tmpl.Execute(viewBuffer, map[string]interface{}{
+		"Prompt":        m.Prompt,
+		"Icon":          m.Icon,
+		"Actions":       m.Actions,
+		"SelectedIndex": m.currentIdx,
+		"DefaultIndex":  m.DefaultIndex,
+		"TerminalWidth": m.width,
+	})
+	if err != nil {
+		m.Err = err
+
+		return "Template Error: " + err.Error()
+	}
+
+	return m.wrap(viewBuffer.String())
+}
+
+func (m *ChoiceModel) resultView() (string, error) {
+	viewBuffer := &bytes.Buffer{}
+
+	if m.ResultTemplate == "" {
+		return "", nil
+	}
+
+	if m.resultTmpl == nil {
+		return "", fmt.Errorf("rendering choice without loaded template")
+	}
+
+	action, err := m.Value()
+	if err != nil {
+		return "", err
+	}
+
+	err = m.resultTmpl.Execute(viewBuffer, map[string]interface{}{
+		"FinalAction":   action,
+		"FinalIndex":    m.currentIdx,
+		"FinalColor":    orDefault(m.Theme.SelectedColor, DefaultYesColor),
+		"Prompt":        m.Prompt,
+		"ResultIcon":    m.ResultIcon,
+		"Actions":       m.Actions,
+		"TerminalWidth": m.width,
+	})
+	if err != nil {
+		return "", fmt.Errorf("execute choice template: %w", err)
+	}
+
+	return viewBuffer.String(), nil
+}
+
+// quit returns tea.Quit unless the prompt is managed by a parent program, in
+// which case it returns a command that emits a ChoiceDoneMsg.
+func (m *ChoiceModel) quit() tea.Cmd {
+	if m.Managed {
+		m.quitting = true
+		value, err := m.Value()
+
+		return func() tea.Msg {
+			return ChoiceDoneMsg{Value: value, Err: err}
+		}
+	}
+
+	return tea.Quit
+}
+
+func (m *ChoiceModel) wrap(text string) string {
+	if m.TrimBlankLines {
+		text = promptkit.TrimBlankLines(text)
+	}
+
+	if m.WrapMode == nil {
+		return text
+	}
+
+	return m.WrapMode(text, m.width)
+}
+
+// Value returns the label of the currently selected action and error.
+func (m *ChoiceModel) Value() (string, error) {
+	idx, err := m.Index()
+	if err != nil {
+		return "", err
+	}
+
+	return m.Actions[idx], nil
+}
+
+// Index returns the index of the currently selected action and error.
+func (m *ChoiceModel) Index() (int, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+
+	if m.currentIdx < 0 || m.currentIdx >= len(m.Actions) {
+		return 0, fmt.Errorf("action index out of bounds")
+	}
+
+	return m.currentIdx, nil
+}
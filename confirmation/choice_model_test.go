@@ -0,0 +1,152 @@
+package confirmation_test
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/confirmation"
+	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/termenv"
+)
+
+func TestChoice(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.NewChoice("file exists:", "Overwrite", "Skip", "Cancel")
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewChoiceModel(c)
+
+	test.Run(t, m)
+	assertNoChoiceError(t, m)
+	test.AssertGoldenView(t, m, "choice_default.golden")
+
+	test.Update(t, m, tea.KeyRight)
+	test.Update(t, m, tea.KeyRight)
+	test.Update(t, m, tea.KeyRight)
+	test.AssertGoldenView(t, m, "choice_last.golden")
+
+	if action := getAction(t, m); action != "Cancel" {
+		t.Errorf("unexpected action: %q, expected Cancel", action)
+	}
+
+	test.Update(t, m, tea.KeyLeft)
+
+	cmd := test.Update(t, m, tea.KeyEnter)
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Errorf("enter did not produce quit signal")
+	}
+
+	if action := getAction(t, m); action != "Skip" {
+		t.Errorf("unexpected action: %q, expected Skip", action)
+	}
+
+	test.AssertGoldenView(t, m, "choice_result.golden")
+}
+
+func TestChoiceTheme(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.NewChoice("file exists:", "Overwrite", "Skip", "Cancel")
+	c.Theme = promptkit.Theme{SelectedColor: "5"}
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewChoiceModel(c)
+
+	test.Run(t, m, tea.KeyEnter)
+	assertNoChoiceError(t, m)
+
+	action := termenv.TrueColor.String("Overwrite").Foreground(termenv.TrueColor.Color("5")).String()
+	if view := m.View(); view != "file exists: "+action+"\n" {
+		t.Errorf("result was not rendered with the selected color: %q", view)
+	}
+}
+
+func TestChoiceLoopCursor(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.NewChoice("file exists:", "Overwrite", "Skip", "Cancel")
+	c.LoopCursor = true
+	m := confirmation.NewChoiceModel(c)
+
+	test.Run(t, m, tea.KeyLeft)
+	assertNoChoiceError(t, m)
+
+	if action := getAction(t, m); action != "Cancel" {
+		t.Errorf("unexpected action: %q, expected Cancel", action)
+	}
+
+	test.Update(t, m, tea.KeyRight)
+
+	if action := getAction(t, m); action != "Overwrite" {
+		t.Errorf("unexpected action: %q, expected Overwrite", action)
+	}
+}
+
+func TestChoiceAbort(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.NewChoice("file exists:", "Overwrite", "Skip", "Cancel")
+	m := confirmation.NewChoiceModel(c)
+
+	test.Run(t, m, tea.KeyCtrlC)
+
+	if !errors.Is(m.Err, promptkit.ErrAborted) {
+		t.Fatalf("aborting produced %v instead of %q", m.Err, promptkit.ErrAborted)
+	}
+
+	m = confirmation.NewChoiceModel(c)
+
+	test.Run(t, m, tea.KeyEsc)
+
+	if !errors.Is(m.Err, promptkit.ErrAborted) {
+		t.Fatalf("esc produced %v instead of %q", m.Err, promptkit.ErrAborted)
+	}
+}
+
+func TestChoiceManaged(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.NewChoice("file exists:", "Overwrite", "Skip", "Cancel")
+	c.Managed = true
+	m := confirmation.NewChoiceModel(c)
+
+	test.Run(t, m)
+
+	cmd := test.Update(t, m, tea.KeyEnter)
+	if cmd == nil {
+		t.Fatalf("managed model did not report its conclusion")
+	}
+
+	done, ok := cmd().(confirmation.ChoiceDoneMsg)
+	if !ok || done.Value != "Overwrite" || done.Err != nil {
+		t.Errorf("unexpected conclusion %#v", done)
+	}
+
+	if cmd := test.Update(t, m, tea.KeyRight); cmd != nil {
+		t.Errorf("concluded model still updates")
+	}
+
+	if action := getAction(t, m); action != "Overwrite" {
+		t.Errorf("action changed after the conclusion to %q", action)
+	}
+}
+
+func getAction(tb testing.TB, m *confirmation.ChoiceModel) string {
+	tb.Helper()
+
+	v, err := m.Value()
+	if err != nil {
+		tb.Fatalf("value: %v", err)
+	}
+
+	return v
+}
+
+func assertNoChoiceError(tb testing.TB, m *confirmation.ChoiceModel) {
+	tb.Helper()
+
+	if m.Err != nil {
+		tb.Fatalf("model contains error: %v", m.Err)
+	}
+}
@@ -0,0 +1,107 @@
+package confirmation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/internal/templates"
+)
+
+// DebugConfig returns a human-readable description of the effective
+// configuration of the confirmation prompt. It is intended to be included in
+// bug reports and its format is not stable.
+func (c *Confirmation) DebugConfig() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Prompt: %q\n", c.Prompt)
+	fmt.Fprintf(&b, "Icon: %q\n", c.Icon)
+	fmt.Fprintf(&b, "ResultIcon: %q\n", c.ResultIcon)
+	fmt.Fprintf(&b, "Preview: %d lines\n", strings.Count(c.Preview, "\n")+1)
+	fmt.Fprintf(&b, "DefaultValue: %s\n", debugValue(c.DefaultValue))
+	fmt.Fprintf(&b, "ConfirmHold: %s\n", c.ConfirmHold)
+	fmt.Fprintf(&b, "Validate: %t\n", c.Validate != nil)
+	fmt.Fprintf(&b, "Required: %t\n", c.Required)
+	fmt.Fprintf(&b, "YesLockout: %s\n", c.YesLockout)
+	fmt.Fprintf(&b, "Timeout: %s\n", c.Timeout)
+	fmt.Fprintf(&b, "StateStore: %T\n", c.StateStore)
+	fmt.Fprintf(&b, "StateKey: %q\n", c.StateKey)
+	fmt.Fprintf(&b, "AuditWriter: %T\n", c.AuditWriter)
+	fmt.Fprintf(&b, "Context: %T\n", c.Context)
+	fmt.Fprintf(&b, "OnChange: %t\n", c.OnChange != nil)
+	fmt.Fprintf(&b, "OnResult: %t\n", c.OnResult != nil)
+	fmt.Fprintf(&b, "Template: %s\n", templates.Name(c.Template, Templates))
+	fmt.Fprintf(&b, "YesLabel: %q\n", c.YesLabel)
+	fmt.Fprintf(&b, "NoLabel: %q\n", c.NoLabel)
+	fmt.Fprintf(&b, "SelectedGlyph: %q\n", c.SelectedGlyph)
+	fmt.Fprintf(&b, "UnselectedGlyph: %q\n", c.UnselectedGlyph)
+	fmt.Fprintf(&b, "Vertical: %t\n", c.Vertical)
+	fmt.Fprintf(&b, "ResultTemplate: %s\n", templates.Name(c.ResultTemplate, ResultTemplates))
+	fmt.Fprintf(&b, "Theme: %+v\n", c.Theme)
+	fmt.Fprintf(&b, "YesColor: %q\n", c.YesColor)
+	fmt.Fprintf(&b, "NoColor: %q\n", c.NoColor)
+	fmt.Fprintf(&b, "EchoAnswer: %t\n", c.EchoAnswer)
+	fmt.Fprintf(&b, "ExtendedTemplateFuncs: %s\n", templates.FuncNames(c.ExtendedTemplateFuncs))
+	fmt.Fprintf(&b, "KeyMap: %+v\n", c.KeyMap)
+	fmt.Fprintf(&b, "ToggleStart: %s\n", debugValue(c.ToggleStart))
+	fmt.Fprintf(&b, "WrapMode: %t\n", c.WrapMode != nil)
+	fmt.Fprintf(&b, "TrimBlankLines: %t\n", c.TrimBlankLines)
+	fmt.Fprintf(&b, "AltScreen: %t\n", c.AltScreen)
+	fmt.Fprintf(&b, "EnableMouse: %t\n", c.EnableMouse)
+	fmt.Fprintf(&b, "InitialWidth: %d\n", c.InitialWidth)
+	fmt.Fprintf(&b, "Managed: %t\n", c.Managed)
+	fmt.Fprintf(&b, "ForwardUnhandledKeys: %t\n", c.ForwardUnhandledKeys)
+	fmt.Fprintf(&b, "LineEnding: %q\n", c.LineEnding)
+	fmt.Fprintf(&b, "ProgramOptions: %d\n", len(c.ProgramOptions))
+	fmt.Fprintf(&b, "Output: %T\n", c.Output)
+	fmt.Fprintf(&b, "Input: %T\n", c.Input)
+	fmt.Fprintf(&b, "ColorProfile: %d\n", promptkit.ResolveColorProfile(c.ColorProfile))
+
+	return b.String()
+}
+
+// DebugConfig returns a human-readable description of the effective
+// configuration of the choice prompt. It is intended to be included in bug
+// reports and its format is not stable.
+func (c *Choice) DebugConfig() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Prompt: %q\n", c.Prompt)
+	fmt.Fprintf(&b, "Icon: %q\n", c.Icon)
+	fmt.Fprintf(&b, "ResultIcon: %q\n", c.ResultIcon)
+	fmt.Fprintf(&b, "Actions: %q\n", c.Actions)
+	fmt.Fprintf(&b, "DefaultIndex: %d\n", c.DefaultIndex)
+	fmt.Fprintf(&b, "LoopCursor: %t\n", c.LoopCursor)
+	fmt.Fprintf(&b, "Template: %s\n", templates.Name(c.Template,
+		map[string]string{"default": DefaultChoiceTemplate}))
+	fmt.Fprintf(&b, "ResultTemplate: %s\n", templates.Name(c.ResultTemplate,
+		map[string]string{"default": DefaultChoiceResultTemplate}))
+	fmt.Fprintf(&b, "ExtendedTemplateFuncs: %s\n", templates.FuncNames(c.ExtendedTemplateFuncs))
+	fmt.Fprintf(&b, "KeyMap: %+v\n", c.KeyMap)
+	fmt.Fprintf(&b, "WrapMode: %t\n", c.WrapMode != nil)
+	fmt.Fprintf(&b, "TrimBlankLines: %t\n", c.TrimBlankLines)
+	fmt.Fprintf(&b, "AltScreen: %t\n", c.AltScreen)
+	fmt.Fprintf(&b, "InitialWidth: %d\n", c.InitialWidth)
+	fmt.Fprintf(&b, "Managed: %t\n", c.Managed)
+	fmt.Fprintf(&b, "ForwardUnhandledKeys: %t\n", c.ForwardUnhandledKeys)
+	fmt.Fprintf(&b, "LineEnding: %q\n", c.LineEnding)
+	fmt.Fprintf(&b, "ProgramOptions: %d\n", len(c.ProgramOptions))
+	fmt.Fprintf(&b, "Output: %T\n", c.Output)
+	fmt.Fprintf(&b, "Input: %T\n", c.Input)
+	fmt.Fprintf(&b, "ColorProfile: %d\n", promptkit.ResolveColorProfile(c.ColorProfile))
+
+	return b.String()
+}
+
+func debugValue(value Value) string {
+	switch value {
+	case Undecided:
+		return "Undecided"
+	case Yes:
+		return "Yes"
+	case No:
+		return "No"
+	default:
+		return fmt.Sprintf("%t", *value)
+	}
+}
@@ -0,0 +1,45 @@
+package confirmation
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ParseValue parses "yes", "y", "true" and "1" as Yes and "no", "n", "false"
+// and "0" as No, ignoring case and surrounding whitespace. It can be used to
+// derive the DefaultValue from a command line flag. Other values are rejected
+// with an error.
+func ParseValue(s string) (Value, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "yes", "y", "true", "1":
+		return Yes, nil
+	case "no", "n", "false", "0":
+		return No, nil
+	default:
+		return Undecided, fmt.Errorf("invalid confirmation value %q", s)
+	}
+}
+
+// DefaultValueFromEnv sets the DefaultValue from the environment variable with
+// the given name as parsed by ParseValue, for example CONFIRM_DEFAULT=yes. If
+// the variable is set and not empty, it takes precedence over the DefaultValue
+// that was configured before, otherwise the DefaultValue is left unchanged. A
+// value that cannot be parsed is reported as an error and also leaves the
+// DefaultValue unchanged. Note that an answer that was stored in the
+// StateStore still takes precedence over the DefaultValue.
+func (c *Confirmation) DefaultValueFromEnv(name string) error {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return nil
+	}
+
+	value, err := ParseValue(raw)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", name, err)
+	}
+
+	c.DefaultValue = value
+
+	return nil
+}
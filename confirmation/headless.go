@@ -0,0 +1,101 @@
+package confirmation
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/internal/headless"
+)
+
+// runHeadless resolves the confirmation from a single line of the input
+// instead of starting the interactive prompt. The line is parsed by
+// parseAnswer and an empty or unrecognized line resolves to the default value.
+// The result is rendered to the output.
+func (c *Confirmation) runHeadless() (Result, error) {
+	line, err := headless.ReadLine(c.Input)
+	if err != nil {
+		return Result{}, err
+	}
+
+	m := NewModel(c)
+
+	m.Init()
+
+	if m.Err != nil {
+		return Result{}, m.Err
+	}
+
+	switch m.parseAnswer(line) {
+	case Yes:
+		m.value = Yes
+		m.selectionMethod = selectionMethodYesKey
+	case No:
+		m.value = No
+		m.selectionMethod = selectionMethodNoKey
+	default:
+		if m.defaultValue == Undecided {
+			return Result{}, fmt.Errorf("cannot parse answer %q and no default value is configured", line)
+		}
+
+		m.value = m.defaultValue
+	}
+
+	if !m.validate() {
+		return Result{}, fmt.Errorf("validate answer: %w", m.validationErr)
+	}
+
+	m.conclude()
+
+	view := m.View()
+	if m.Err != nil {
+		return Result{}, m.Err
+	}
+
+	if view != "" {
+		_, err = io.WriteString(c.Output, promptkit.ConvertLineEndings(view, c.LineEnding))
+		if err != nil {
+			return Result{}, fmt.Errorf("writing result: %w", err)
+		}
+	}
+
+	return m.Result()
+}
+
+// parseAnswer parses a headless answer as Yes or No. Besides the values that
+// are accepted by ParseValue, the YesLabel and NoLabel (case-insensitive) and
+// the Yes and No keys of the KeyMap are recognized, which take precedence such
+// that a localized prompt accepts its own answers. Unrecognized answers are
+// returned as Undecided.
+func (m *Model) parseAnswer(line string) Value {
+	answer := strings.TrimSpace(line)
+
+	switch {
+	case answer == "":
+		return Undecided
+	case strings.EqualFold(answer, m.yesLabel()) || keyMatchesString(answer, m.KeyMap.Yes):
+		return Yes
+	case strings.EqualFold(answer, m.noLabel()) || keyMatchesString(answer, m.KeyMap.No):
+		return No
+	}
+
+	value, err := ParseValue(answer)
+	if err != nil {
+		return Undecided
+	}
+
+	return value
+}
+
+// keyMatchesString returns true if the answer is one of the keys of the
+// mapping.
+func keyMatchesString(answer string, mapping []string) bool {
+	for _, key := range mapping {
+		if key == answer {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,124 @@
+package confirmation
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyDescriptions holds the human-readable descriptions of the actions of a
+// KeyMap that are used by its help. Empty descriptions are replaced by the
+// ones of DefaultKeyDescriptions. Submit describes both the SubmitSelected and
+// the Submit keys.
+type KeyDescriptions struct {
+	Yes         string
+	No          string
+	SelectYes   string
+	SelectNo    string
+	SelectLeft  string
+	SelectRight string
+	Toggle      string
+	Submit      string
+	Abort       string
+	Interrupt   string
+}
+
+// DefaultKeyDescriptions returns the default descriptions of the actions of a
+// KeyMap.
+func DefaultKeyDescriptions() KeyDescriptions {
+	return KeyDescriptions{
+		Yes:         "yes",
+		No:          "no",
+		SelectYes:   "select yes",
+		SelectNo:    "select no",
+		SelectLeft:  "move left",
+		SelectRight: "move right",
+		Toggle:      "toggle",
+		Submit:      "confirm",
+		Abort:       "abort",
+		Interrupt:   "interrupt",
+	}
+}
+
+// ShortHelp returns the bindings of the most important actions. Together with
+// FullHelp, it implements the KeyMap interface of the bubbles help component
+// such that the help can be rendered with it. Actions without keys are
+// omitted.
+func (km *KeyMap) ShortHelp() []key.Binding {
+	d := km.descriptions()
+
+	return bindings(
+		binding(km.Yes, d.Yes),
+		binding(km.No, d.No),
+		binding(km.Toggle, d.Toggle),
+		binding(km.submitKeys(), d.Submit),
+	)
+}
+
+// FullHelp returns the bindings of all actions grouped into columns.
+func (km *KeyMap) FullHelp() [][]key.Binding {
+	d := km.descriptions()
+
+	return [][]key.Binding{
+		bindings(binding(km.Yes, d.Yes), binding(km.No, d.No), binding(km.submitKeys(), d.Submit)),
+		bindings(binding(km.SelectYes, d.SelectYes), binding(km.SelectNo, d.SelectNo),
+			binding(km.SelectLeft, d.SelectLeft), binding(km.SelectRight, d.SelectRight),
+			binding(km.Toggle, d.Toggle)),
+		bindings(binding(km.Abort, d.Abort), binding(km.Interrupt, d.Interrupt)),
+	}
+}
+
+// submitKeys returns the SubmitSelected keys followed by the Submit keys.
+func (km *KeyMap) submitKeys() []string {
+	return append(append([]string{}, km.SubmitSelected...), km.Submit...)
+}
+
+// descriptions returns the configured descriptions with the defaults for the
+// empty ones.
+func (km *KeyMap) descriptions() KeyDescriptions {
+	d := km.Descriptions
+	defaults := DefaultKeyDescriptions()
+
+	d.Yes = orDefault(d.Yes, defaults.Yes)
+	d.No = orDefault(d.No, defaults.No)
+	d.SelectYes = orDefault(d.SelectYes, defaults.SelectYes)
+	d.SelectNo = orDefault(d.SelectNo, defaults.SelectNo)
+	d.SelectLeft = orDefault(d.SelectLeft, defaults.SelectLeft)
+	d.SelectRight = orDefault(d.SelectRight, defaults.SelectRight)
+	d.Toggle = orDefault(d.Toggle, defaults.Toggle)
+	d.Submit = orDefault(d.Submit, defaults.Submit)
+	d.Abort = orDefault(d.Abort, defaults.Abort)
+	d.Interrupt = orDefault(d.Interrupt, defaults.Interrupt)
+
+	return d
+}
+
+func binding(keys []string, description string) key.Binding {
+	names := make([]string, 0, len(keys))
+
+	for _, k := range keys {
+		if k == " " {
+			k = "space"
+		}
+
+		names = append(names, k)
+	}
+
+	return key.NewBinding(
+		key.WithKeys(keys...),
+		key.WithHelp(strings.Join(names, "/"), description),
+	)
+}
+
+// bindings returns the bindings that have at least one key.
+func bindings(all ...key.Binding) []key.Binding {
+	enabled := make([]key.Binding, 0, len(all))
+
+	for _, b := range all {
+		if b.Enabled() {
+			enabled = append(enabled, b)
+		}
+	}
+
+	return enabled
+}
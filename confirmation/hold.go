@@ -0,0 +1,90 @@
+package confirmation
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// armTickInterval is the interval in which the view is refreshed while Yes is
+// armed such that the remaining window can be rendered.
+const armTickInterval = 100 * time.Millisecond
+
+// armTickMsg refreshes the view while Yes is armed and disarms it when the
+// ConfirmHold window has passed.
+type armTickMsg struct {
+	generation int
+}
+
+// submit concludes the prompt with the current value unless it does not pass
+// Validate or ConfirmHold requires Yes to be confirmed twice, in which case the
+// first confirmation only arms Yes.
+func (m *Model) submit() tea.Cmd {
+	if !m.validate() {
+		m.disarm()
+
+		return nil
+	}
+
+	if m.ConfirmHold <= 0 || m.value != Yes {
+		return m.conclude()
+	}
+
+	if m.armed && time.Since(m.armedAt) <= m.ConfirmHold {
+		return m.conclude()
+	}
+
+	m.armed = true
+	m.armedAt = time.Now()
+	m.armGeneration++
+
+	return m.armTick()
+}
+
+// disarm cancels an armed Yes, for example because the selection changed.
+func (m *Model) disarm() {
+	m.armed = false
+	m.armGeneration++
+}
+
+func (m *Model) armTick() tea.Cmd {
+	generation := m.armGeneration
+
+	next := m.armRemaining()
+	if next > armTickInterval {
+		next = armTickInterval
+	}
+
+	return tea.Tick(next, func(time.Time) tea.Msg {
+		return armTickMsg{generation: generation}
+	})
+}
+
+func (m *Model) handleArmTick(msg armTickMsg) tea.Cmd {
+	if !m.armed || m.quitting || msg.generation != m.armGeneration {
+		return nil
+	}
+
+	if m.armRemaining() <= 0 {
+		m.disarm()
+
+		return nil
+	}
+
+	return m.armTick()
+}
+
+// armRemaining returns the remaining ConfirmHold window for the second
+// confirmation or 0 if Yes is not armed.
+func (m *Model) armRemaining() time.Duration {
+	if !m.armed {
+		return 0
+	}
+
+	remaining := m.ConfirmHold - time.Since(m.armedAt)
+	if remaining < 0 {
+		return 0
+	}
+
+	return remaining
+}
@@ -10,25 +10,82 @@ import (
 // also be used as a starting point for customization.
 func NewDefaultKeyMap() *KeyMap {
 	return &KeyMap{
-		Yes:       []string{"y", "Y"},
-		No:        []string{"n", "N"},
-		SelectYes: []string{"left"},
-		SelectNo:  []string{"right"},
-		Toggle:    []string{"tab"},
-		Submit:    []string{"enter"},
-		Abort:     []string{"ctrl+c"},
+		Yes:            []string{"y", "Y"},
+		No:             []string{"n", "N"},
+		SelectYes:      []string{"up"},
+		SelectNo:       []string{"down"},
+		SelectLeft:     []string{"left"},
+		SelectRight:    []string{"right"},
+		Toggle:         []string{"tab", " "},
+		SubmitSelected: []string{"enter"},
+		Submit:         []string{},
+		Abort:          []string{"ctrl+c", "esc"},
+		Interrupt:      []string{},
+
+		Descriptions: DefaultKeyDescriptions(),
 	}
 }
 
-// KeyMap defines the keys that trigger certain actions.
+// KeyMap defines the keys that trigger certain actions. The Abort keys abort
+// the prompt with promptkit.ErrAborted and the Interrupt keys abort it with
+// promptkit.ErrInterrupted such that the parent program can distinguish them.
+// By default, ctrl+c and esc are Abort keys and no Interrupt keys are
+// configured. An aborted prompt is never reported as No, RunPrompt and Value
+// return promptkit.ErrAborted instead such that callers can use errors.Is to
+// handle it, for example by exiting with status code 130.
+//
+// The SubmitSelected keys confirm the currently selected value which is
+// initially the DefaultValue, so with a DefaultValue of Yes, pressing enter
+// immediately confirms Yes. While the value is Undecided, the SubmitSelected
+// keys are ignored and the prompt stays open. The Submit keys are kept for
+// compatibility and behave exactly like the SubmitSelected keys, by default
+// none are configured. In contrast, the Yes and No keys always confirm Yes
+// or No regardless of the selection and the SelectYes, SelectNo, SelectLeft,
+// SelectRight and Toggle keys only change the selection without confirming
+// it. The SelectLeft and SelectRight keys move the selection between Yes on
+// the left and No on the right with wraparound and start at Yes or No
+// respectively while the value is Undecided. The Toggle keys switch between
+// Yes and No and start at the ToggleStart of the Confirmation.
+//
+// The Descriptions are shown next to the keys in the help that is provided by
+// ShortHelp and FullHelp which can be rendered with the bubbles help
+// component or in the Template.
 type KeyMap struct {
-	Yes       []string
-	No        []string
-	SelectYes []string
-	SelectNo  []string
-	Toggle    []string
+	Yes            []string
+	No             []string
+	SelectYes      []string
+	SelectNo       []string
+	SelectLeft     []string
+	SelectRight    []string
+	Toggle         []string
+	SubmitSelected []string
+	Submit         []string
+	Abort          []string
+	Interrupt      []string
+
+	Descriptions KeyDescriptions
+}
+
+// NewDefaultChoiceKeyMap returns a ChoiceKeyMap with sensible default key
+// mappings that can also be used as a starting point for customization.
+func NewDefaultChoiceKeyMap() *ChoiceKeyMap {
+	return &ChoiceKeyMap{
+		Previous:  []string{"left", "shift+tab"},
+		Next:      []string{"right", "tab"},
+		Submit:    []string{"enter"},
+		Abort:     []string{"ctrl+c", "esc"},
+		Interrupt: []string{},
+	}
+}
+
+// ChoiceKeyMap defines the keys that trigger certain actions in a Choice
+// prompt. The Abort and Interrupt keys behave like the ones of KeyMap.
+type ChoiceKeyMap struct {
+	Previous  []string
+	Next      []string
 	Submit    []string
 	Abort     []string
+	Interrupt []string
 }
 
 func keyMatches(key tea.KeyMsg, mapping []string) bool {
@@ -45,7 +102,7 @@ func keyMatches(key tea.KeyMsg, mapping []string) bool {
 // least the bare minimum set of key bindings for the functional
 // prompt and false otherwise.
 func validateKeyMap(km *KeyMap) error {
-	if len(km.Yes) == 0 && len(km.No) == 0 && len(km.Submit) == 0 {
+	if len(km.Yes) == 0 && len(km.No) == 0 && len(km.SubmitSelected) == 0 && len(km.Submit) == 0 {
 		return fmt.Errorf("no submit key")
 	}
 
@@ -57,3 +114,18 @@ func validateKeyMap(km *KeyMap) error {
 
 	return nil
 }
+
+// validateChoiceKeyMap returns true if the given choice key map contains at
+// least the bare minimum set of key bindings for the functional prompt and
+// false otherwise.
+func validateChoiceKeyMap(km *ChoiceKeyMap) error {
+	if len(km.Submit) == 0 {
+		return fmt.Errorf("no submit key")
+	}
+
+	if len(km.Previous) == 0 && len(km.Next) == 0 {
+		return fmt.Errorf("missing keys to select an action")
+	}
+
+	return nil
+}
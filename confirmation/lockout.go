@@ -0,0 +1,47 @@
+package confirmation
+
+import (
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// lockoutMsg is sent periodically while Yes is locked due to YesLockout to
+// update the remaining time in the view.
+type lockoutMsg struct{}
+
+// lockoutCountdown returns a command that sends the next lockoutMsg at the next
+// full second of the remaining lockout or when the lockout ends.
+func (m *Model) lockoutCountdown() tea.Cmd {
+	remaining := time.Until(m.lockoutDeadline)
+
+	next := remaining % time.Second
+	if next <= 0 {
+		next = time.Second
+	}
+
+	if remaining < next {
+		next = remaining
+	}
+
+	return tea.Tick(next, func(time.Time) tea.Msg {
+		return lockoutMsg{}
+	})
+}
+
+// yesLocked returns whether Yes can currently neither be selected nor
+// confirmed due to YesLockout.
+func (m *Model) yesLocked() bool {
+	return time.Now().Before(m.lockoutDeadline)
+}
+
+// lockoutSeconds returns the number of seconds until Yes becomes available,
+// rounded up, or 0 if Yes is not locked.
+func (m *Model) lockoutSeconds() int {
+	if !m.yesLocked() {
+		return 0
+	}
+
+	return int(math.Ceil(time.Until(m.lockoutDeadline).Seconds()))
+}
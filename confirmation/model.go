@@ -3,14 +3,46 @@ package confirmation
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"text/template"
+	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/internal/keys"
+	"github.com/erikgeiser/promptkit/internal/region"
 	"github.com/muesli/termenv"
 )
 
+// doubleClickInterval is the maximum duration between two clicks on the same
+// value such that they are considered a double-click.
+const doubleClickInterval = 500 * time.Millisecond
+
+// the region IDs of the labels for mouse hit-testing
+const (
+	labelRegionNo = iota
+	labelRegionYes
+)
+
+// The selection methods describe how the final value was chosen. They are
+// available in the ResultTemplate as SelectionMethod.
+const (
+	selectionMethodYesKey  = "yes-key"
+	selectionMethodNoKey   = "no-key"
+	selectionMethodDefault = "default"
+	selectionMethodToggle  = "toggle"
+)
+
+// DoneMsg is emitted by a Managed model when the prompt concludes or aborts
+// instead of quitting the program. Value and Err correspond to the result of
+// Model.Value. Afterwards, the model ignores all messages.
+type DoneMsg struct {
+	Value Value
+	Err   error
+}
+
 // Model implements the bubbletea.Model for a confirmation prompt.
 type Model struct {
 	*Confirmation
@@ -25,11 +57,53 @@ type Model struct {
 	tmpl       *template.Template
 	resultTmpl *template.Template
 
-	value Value
+	value        Value
+	defaultValue Value
 
 	quitting bool
 
 	width int
+
+	lastClickedValue Value
+	lastClickTime    time.Time
+
+	// labelRegions holds where the labels were rendered in the last view for
+	// mouse hit-testing
+	labelRegions []region.Region
+
+	commands chan tea.Msg
+
+	deadline time.Time
+
+	// timedOut is set if the prompt resolved due to the Timeout and
+	// remainingOnAnswer holds the countdown time left when it was answered
+	timedOut          bool
+	remainingOnAnswer time.Duration
+
+	// startedAt is set in Init and duration holds the time until the prompt
+	// concluded or was aborted
+	startedAt time.Time
+	duration  time.Duration
+
+	// lockoutDeadline is the time until which Yes is locked due to YesLockout
+	lockoutDeadline time.Time
+
+	// validationErr is the error that Validate returned for validatedValue
+	validationErr  error
+	validatedValue Value
+
+	// requiredHint is set when an Undecided value was submitted while
+	// Required is set
+	requiredHint bool
+
+	// selectionMethod describes how the final value was chosen
+	selectionMethod string
+
+	// armed is set when Yes was confirmed once while ConfirmHold is set and
+	// armGeneration invalidates the ticks of previous arms
+	armed         bool
+	armedAt       time.Time
+	armGeneration int
 }
 
 // ensure that the Model interface is implemented.
@@ -40,31 +114,106 @@ func NewModel(confirmation *Confirmation) *Model {
 	return &Model{
 		Confirmation: confirmation,
 		value:        confirmation.DefaultValue,
+		defaultValue: confirmation.DefaultValue,
+	}
+}
+
+// SelectMsg selects the given value as if the user selected it. It can be sent
+// through the channel returned by Commands.
+type SelectMsg struct {
+	Value Value
+}
+
+// SubmitMsg submits the currently selected value as if the user pressed a
+// Submit key. It can be sent through the channel returned by Commands.
+type SubmitMsg struct{}
+
+// commandMsg wraps a message that was received through the commands channel.
+type commandMsg struct {
+	msg tea.Msg
+}
+
+// Commands returns a channel through which other goroutines can control the
+// prompt, for example in tests or for remote control. Any message such as a
+// SelectMsg, a SubmitMsg or a tea.KeyMsg can be sent through the channel and
+// it is processed in the bubbletea event loop as if it was received by the
+// program, so no further synchronization is required. Commands must be called
+// before the prompt is started. The channel is unbuffered and stops being read
+// once the prompt has concluded, so sends after that block forever and should
+// be guarded, for example with a select statement.
+func (m *Model) Commands() chan<- tea.Msg {
+	if m.commands == nil {
+		m.commands = make(chan tea.Msg)
+	}
+
+	return m.commands
+}
+
+func (m *Model) waitForCommand() tea.Cmd {
+	if m.commands == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		return commandMsg{msg: <-m.commands}
 	}
 }
 
 // Init initializes the confirmation prompt model.
 func (m *Model) Init() tea.Cmd {
+	m.startedAt = time.Now()
+	m.width = zeroAwareMin(m.InitialWidth, m.MaxWidth)
+
+	m.Err = m.loadState()
+	if m.Err != nil {
+		return m.quit()
+	}
+
 	m.tmpl, m.Err = m.initTemplate()
 	if m.Err != nil {
-		return tea.Quit
+		return m.quit()
 	}
 
 	m.resultTmpl, m.Err = m.initResultTemplate()
 	if m.Err != nil {
-		return tea.Quit
+		return m.quit()
+	}
+
+	cmds := []tea.Cmd{textinput.Blink, m.waitForCommand()}
+
+	if m.YesLockout > 0 {
+		m.lockoutDeadline = time.Now().Add(m.YesLockout)
+
+		cmds = append(cmds, m.lockoutCountdown())
+	}
+
+	if m.Timeout > 0 {
+		if m.defaultValue == Undecided {
+			m.Err = errTimeoutUndecided
+
+			return m.quit()
+		}
+
+		m.deadline = time.Now().Add(m.Timeout)
+
+		cmds = append(cmds, m.countdown())
 	}
 
-	return textinput.Blink
+	return tea.Batch(cmds...)
 }
 
 func (m *Model) initTemplate() (*template.Template, error) {
-	tmpl := template.New("view")
-	tmpl.Funcs(termenv.TemplateFuncs(m.ColorProfile))
-	tmpl.Funcs(promptkit.UtilFuncMap())
-	tmpl.Funcs(m.ExtendedTemplateFuncs)
+	tmpl := m.Template
+	if m.Vertical && tmpl == DefaultTemplate {
+		tmpl = TemplateVertical
+	}
 
-	return tmpl.Parse(m.Template)
+	return promptkit.ParseTemplate("view", tmpl,
+		termenv.TemplateFuncs(m.colorProfile()),
+		promptkit.UtilFuncMap(),
+		m.themeFuncs(),
+		m.ExtendedTemplateFuncs,
+	)
 }
 
 func (m *Model) initResultTemplate() (*template.Template, error) {
@@ -72,70 +221,313 @@ func (m *Model) initResultTemplate() (*template.Template, error) {
 		return nil, nil
 	}
 
-	tmpl := template.New("result")
-	tmpl.Funcs(termenv.TemplateFuncs(m.ColorProfile))
-	tmpl.Funcs(promptkit.UtilFuncMap())
-	tmpl.Funcs(m.ExtendedTemplateFuncs)
+	return promptkit.ParseTemplate("result", m.ResultTemplate,
+		termenv.TemplateFuncs(m.colorProfile()),
+		promptkit.UtilFuncMap(),
+		m.themeFuncs(),
+		m.ExtendedTemplateFuncs,
+	)
+}
+
+// themeFuncs returns the template functions of the Theme in which empty colors
+// fall back to the colors of the built-in templates.
+func (m *Model) themeFuncs() template.FuncMap {
+	return m.Theme.WithDefaults(defaultTheme).TemplateFuncs(m.colorProfile())
+}
 
-	return tmpl.Parse(m.ResultTemplate)
+// colorProfile returns the configured ColorProfile unless another profile was
+// forced with promptkit.SetColorProfile.
+func (m *Model) colorProfile() termenv.Profile {
+	return promptkit.ResolveColorProfile(m.ColorProfile)
 }
 
 // Update updates the model based on the received message.
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.Managed && m.quitting {
+		// the parent program was already notified with a DoneMsg
+		return m, nil
+	}
+
+	defer m.notifyChange(m.value)
+
+	return m.update(msg)
+}
+
+// update handles the message without notifying OnChange such that messages
+// that were received through the commands channel can be handled without
+// notifying twice.
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.Err != nil {
-		return m, tea.Quit
+		return m, m.quit()
 	}
 
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case commandMsg:
+		_, cmd = m.update(msg.msg)
+		if m.quitting {
+			return m, cmd
+		}
+
+		return m, tea.Batch(cmd, m.waitForCommand())
+	case SelectMsg:
+		if msg.Value == Yes && m.yesLocked() {
+			return m, nil
+		}
+
+		m.value = msg.Value
+		m.selectionMethod = selectionMethodToggle
+
+		if m.value != Yes {
+			m.disarm()
+		}
+	case SubmitMsg:
+		if m.value != Undecided && !(m.value == Yes && m.yesLocked()) {
+			return m, m.submit()
+		}
+
+		m.requiredHint = m.Required && m.value == Undecided
+	case armTickMsg:
+		return m, m.handleArmTick(msg)
+	case lockoutMsg:
+		if m.quitting || !m.yesLocked() {
+			return m, nil
+		}
+
+		return m, m.lockoutCountdown()
+	case countdownMsg:
+		if m.quitting {
+			return m, nil
+		}
+
+		if time.Now().Before(m.deadline) {
+			return m, m.countdown()
+		}
+
+		if m.defaultValue == Yes && m.yesLocked() {
+			// the timeout must not confirm Yes before the YesLockout ended
+			m.deadline = m.lockoutDeadline
+
+			return m, m.countdown()
+		}
+
+		m.value = m.defaultValue
+		m.selectionMethod = ""
+		m.timedOut = true
+
+		// the default value is submitted like an answer such that Validate and
+		// ConfirmHold apply and the prompt stays open if it is not concluded
+		cmd = m.submit()
+		m.timedOut = m.quitting
+
+		return m, cmd
 	case tea.KeyMsg:
-		switch {
-		case keyMatches(msg, m.KeyMap.Submit):
-			if m.value != Undecided {
-				m.quitting = true
+		keys.Record(m.RecordKeys, msg)
 
-				return m, tea.Quit
+		switch {
+		case keyMatches(msg, m.KeyMap.submitKeys()):
+			if m.value != Undecided && !(m.value == Yes && m.yesLocked()) {
+				return m, m.submit()
 			}
+
+			m.requiredHint = m.Required && m.value == Undecided
+		case keyMatches(msg, m.KeyMap.Interrupt):
+			m.Err = promptkit.ErrInterrupted
+			m.quitting = true
+
+			return m, m.quit()
 		case keyMatches(msg, m.KeyMap.Abort):
 			m.Err = promptkit.ErrAborted
 			m.quitting = true
 
-			return m, tea.Quit
+			return m, m.quit()
 		case keyMatches(msg, m.KeyMap.Yes):
+			if m.yesLocked() {
+				break
+			}
+
 			m.value = Yes
-			m.quitting = true
+			m.selectionMethod = selectionMethodYesKey
 
-			return m, tea.Quit
+			return m, m.submit()
 		case keyMatches(msg, m.KeyMap.No):
 			m.value = No
-			m.quitting = true
+			m.selectionMethod = selectionMethodNoKey
 
-			return m, tea.Quit
+			return m, m.submit()
 		case keyMatches(msg, m.KeyMap.SelectYes):
-			m.value = Yes
+			m.focus(Yes)
 		case keyMatches(msg, m.KeyMap.SelectNo):
-			m.value = No
+			m.focus(No)
+		case keyMatches(msg, m.KeyMap.SelectLeft):
+			m.focus(m.other(Yes))
+		case keyMatches(msg, m.KeyMap.SelectRight):
+			m.focus(m.other(No))
 		case keyMatches(msg, m.KeyMap.Toggle):
-			switch m.value {
-			case Yes:
-				m.value = No
-			case No, Undecided:
-				m.value = Yes
-			}
+			m.focus(m.other(m.toggleStart()))
+		case m.ForwardUnhandledKeys:
+			return m, promptkit.ForwardKey(msg)
+		default: // do nothing
+		}
+	case tea.MouseMsg:
+		if m.EnableMouse {
+			return m.updateMouse(msg)
 		}
 	case tea.WindowSizeMsg:
 		m.width = zeroAwareMin(msg.Width, m.MaxWidth)
 	case error:
 		m.Err = msg
 
-		return m, tea.Quit
+		return m, m.quit()
 	}
 
 	return m, cmd
 }
 
-// View renders the confirmation prompt.
+// validate runs Validate for the current value and returns whether it can be
+// confirmed.
+func (m *Model) validate() bool {
+	if m.Validate == nil {
+		return true
+	}
+
+	m.validationErr = m.Validate(m.value)
+	m.validatedValue = m.value
+
+	return m.validationErr == nil
+}
+
+// currentValidationError returns the error that Validate returned for the
+// currently selected value, if it was validated.
+func (m *Model) currentValidationError() error {
+	if m.validationErr == nil || m.validatedValue != m.value {
+		return nil
+	}
+
+	return m.validationErr
+}
+
+// conclude ends the prompt with the current value, stores it in the
+// StateStore and writes it to the AuditWriter if configured.
+func (m *Model) conclude() tea.Cmd {
+	m.quitting = true
+
+	if m.selectionMethod == "" {
+		m.selectionMethod = selectionMethodDefault
+	}
+
+	m.recordRemaining()
+
+	if m.StateStore != nil && m.StateKey != "" {
+		err := m.StateStore.Set(m.StateKey, stateFromValue(m.value))
+		if err != nil {
+			m.Err = fmt.Errorf("store state: %w", err)
+		}
+	}
+
+	err := m.writeAuditEntry()
+	if err != nil && m.Err == nil {
+		m.Err = err
+	}
+
+	if m.OnResult != nil && m.Err == nil {
+		m.OnResult(m.value, m.Context)
+	}
+
+	return m.quit()
+}
+
+// loadState loads the previous answer from the StateStore if configured and
+// uses it as the default value.
+func (m *Model) loadState() error {
+	if m.StateStore == nil || m.StateKey == "" {
+		return nil
+	}
+
+	state, err := m.StateStore.Get(m.StateKey)
+	if err != nil {
+		return fmt.Errorf("load state: %w", err)
+	}
+
+	value, err := valueFromState(state)
+	if err != nil {
+		return fmt.Errorf("load state: %w", err)
+	}
+
+	if value != Undecided {
+		m.value = value
+		m.defaultValue = value
+	}
+
+	return nil
+}
+
+func (m *Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Type != tea.MouseLeft {
+		return m, nil
+	}
+
+	value := m.valueAt(msg.X, msg.Y)
+	if value == Undecided || (value == Yes && m.yesLocked()) {
+		return m, nil
+	}
+
+	doubleClick := m.lastClickedValue == value &&
+		time.Since(m.lastClickTime) <= doubleClickInterval
+
+	m.value = value
+	m.selectionMethod = selectionMethodToggle
+	m.lastClickedValue = value
+	m.lastClickTime = time.Now()
+
+	if m.value != Yes {
+		m.disarm()
+	}
+
+	if doubleClick {
+		return m, m.submit()
+	}
+
+	return m, nil
+}
+
+// valueAt returns the value that is rendered at the given position of the view
+// or Undecided if no value is rendered there. The position is looked up in the
+// regions that were recorded when the YesLabel and NoLabel were last rendered,
+// so the same text elsewhere in the view, such as in the prompt, is ignored.
+func (m *Model) valueAt(x int, y int) Value {
+	// render the view to record the regions of the current state
+	_ = m.View()
+
+	for _, r := range m.labelRegions {
+		if !r.Contains(x, y) {
+			continue
+		}
+
+		if r.ID == labelRegionYes {
+			return Yes
+		}
+
+		return No
+	}
+
+	return Undecided
+}
+
+// markLabel marks the label of Yes or No for mouse hit-testing if EnableMouse
+// is set.
+func (m *Model) markLabel(id int, label string) string {
+	if !m.EnableMouse {
+		return label
+	}
+
+	return region.Mark(id, label)
+}
+
+// View renders the confirmation prompt for the current state of the model. It
+// only executes the templates and neither reads input nor touches the terminal
+// such that it can also be used to snapshot the prompt, see also Render.
 func (m *Model) View() string {
 	// avoid panics if Quit is sent during Init
 	if m.quitting {
@@ -156,15 +548,38 @@ func (m *Model) View() string {
 
 	viewBuffer := &bytes.Buffer{}
 
+	validationErr := m.currentValidationError()
+
+	errorMessage := ""
+	if validationErr != nil {
+		errorMessage = validationErr.Error()
+	}
+
 	err := m.tmpl.Execute(viewBuffer, map[string]interface{}{
 		"Prompt":           m.Prompt,
+		"PromptLines":      m.promptLines(),
+		"Icon":             m.Icon,
 		"YesSelected":      m.value == Yes,
 		"NoSelected":       m.value == No,
+		"YesLabel":         m.markLabel(labelRegionYes, m.yesLabel()),
+		"NoLabel":          m.markLabel(labelRegionNo, m.noLabel()),
+		"YesKey":           acceleratorKey(m.KeyMap.Yes, m.yesLabel()),
+		"NoKey":            acceleratorKey(m.KeyMap.No, m.noLabel()),
 		"Undecided":        m.value == Undecided,
-		"DefaultYes":       m.DefaultValue == Yes,
-		"DefaultNo":        m.DefaultValue == No,
-		"DefaultUndecided": m.DefaultValue == Undecided,
+		"ValidationError":  validationErr,
+		"ErrorMessage":     errorMessage,
+		"ShowRequiredHint": m.requiredHint && m.value == Undecided,
+		"DefaultYes":       m.defaultValue == Yes,
+		"DefaultNo":        m.defaultValue == No,
+		"DefaultUndecided": m.defaultValue == Undecided,
+		"RemainingSeconds": m.remainingSeconds(),
+		"LockoutSeconds":   m.lockoutSeconds(),
+		"Armed":            m.armed,
+		"ArmRemaining":     m.armRemaining().Round(armTickInterval),
+		"SelectedGlyph":    orDefault(m.SelectedGlyph, DefaultSelectedGlyph),
+		"UnselectedGlyph":  orDefault(m.UnselectedGlyph, DefaultUnselectedGlyph),
 		"TerminalWidth":    m.width,
+		"Help":             m.KeyMap.ShortHelp(),
 	})
 	if err != nil {
 		m.Err = err
@@ -172,12 +587,39 @@ func (m *Model) View() string {
 		return "Template Error: " + err.Error()
 	}
 
-	return m.wrap(viewBuffer.String())
+	view, regions := region.Extract(m.preview() + m.wrap(viewBuffer.String()))
+	m.labelRegions = regions
+
+	return view
+}
+
+// preview returns the Preview hard-wrapped to the terminal width followed by a
+// newline or an empty string if no preview is configured.
+func (m *Model) preview() string {
+	if m.Preview == "" {
+		return ""
+	}
+
+	return promptkit.HardWrap(strings.TrimSuffix(m.Preview, "\n"), m.width) + "\n"
 }
 
 func (m *Model) resultView() (string, error) {
 	viewBuffer := &bytes.Buffer{}
 
+	if m.EchoAnswer {
+		value, err := m.decision()
+		if err != nil {
+			return "", err
+		}
+
+		answer := m.noLabel()
+		if value {
+			answer = m.yesLabel()
+		}
+
+		return m.Prompt + " " + answer + "\n", nil
+	}
+
 	if m.ResultTemplate == "" {
 		return "", nil
 	}
@@ -186,7 +628,7 @@ func (m *Model) resultView() (string, error) {
 		return "", fmt.Errorf("rendering confirmation without loaded template")
 	}
 
-	value, err := m.Value()
+	value, err := m.decision()
 	if err != nil {
 		return "", err
 	}
@@ -194,10 +636,19 @@ func (m *Model) resultView() (string, error) {
 	err = m.resultTmpl.Execute(viewBuffer, map[string]interface{}{
 		"FinalValue":       value,
 		"FinalValueString": fmt.Sprintf("%v", value),
+		"YesLabel":         m.yesLabel(),
+		"NoLabel":          m.noLabel(),
+		"YesKey":           acceleratorKey(m.KeyMap.Yes, m.yesLabel()),
+		"NoKey":            acceleratorKey(m.KeyMap.No, m.noLabel()),
 		"Prompt":           m.Prompt,
-		"DefaultYes":       m.DefaultValue == Yes,
-		"DefaultNo":        m.DefaultValue == No,
-		"DefaultUndecided": m.DefaultValue == Undecided,
+		"PromptLines":      m.promptLines(),
+		"ResultIcon":       m.ResultIcon,
+		"DefaultYes":       m.defaultValue == Yes,
+		"DefaultNo":        m.defaultValue == No,
+		"DefaultUndecided": m.defaultValue == Undecided,
+		"SelectionMethod":  m.selectionMethod,
+		"YesColor":         orDefault(m.YesColor, DefaultYesColor),
+		"NoColor":          orDefault(m.NoColor, DefaultNoColor),
 		"TerminalWidth":    m.width,
 	})
 	if err != nil {
@@ -207,7 +658,28 @@ func (m *Model) resultView() (string, error) {
 	return viewBuffer.String(), nil
 }
 
+// quit returns tea.Quit unless the prompt is managed by a parent program, in
+// which case it returns a command that emits a DoneMsg.
+func (m *Model) quit() tea.Cmd {
+	m.recordDuration()
+
+	if m.Managed {
+		m.quitting = true
+		value, err := m.Value()
+
+		return func() tea.Msg {
+			return DoneMsg{Value: value, Err: err}
+		}
+	}
+
+	return tea.Quit
+}
+
 func (m *Model) wrap(text string) string {
+	if m.TrimBlankLines {
+		text = promptkit.TrimBlankLines(text)
+	}
+
 	if m.WrapMode == nil {
 		return text
 	}
@@ -215,17 +687,107 @@ func (m *Model) wrap(text string) string {
 	return m.WrapMode(text, m.width)
 }
 
-// Value returns the current value and error.
-func (m *Model) Value() (bool, error) {
+// focus selects the given value without confirming it. Yes cannot be selected
+// while it is locked due to YesLockout.
+func (m *Model) focus(value Value) {
+	if value == Yes && m.yesLocked() {
+		return
+	}
+
+	if value != Yes {
+		m.disarm()
+	}
+
+	m.value = value
+	m.selectionMethod = selectionMethodToggle
+}
+
+// promptLines returns the lines of the prompt without trailing line breaks.
+func (m *Model) promptLines() []string {
+	return strings.Split(strings.TrimRight(m.Prompt, "\n"), "\n")
+}
+
+// notifyChange calls OnChange if the value differs from the previous value.
+func (m *Model) notifyChange(previous Value) {
+	if m.OnChange == nil || m.value == previous {
+		return
+	}
+
+	m.OnChange(m.value, m.Context)
+}
+
+// other returns the value that is not currently selected. As there are only
+// two values, this moves the selection with wraparound in either direction.
+// While the value is Undecided, the given start value is returned instead.
+func (m *Model) other(start Value) Value {
+	switch m.value {
+	case Yes:
+		return No
+	case No:
+		return Yes
+	default:
+		return start
+	}
+}
+
+// toggleStart returns the value that the Toggle keys select while the value
+// is Undecided.
+func (m *Model) toggleStart() Value {
+	if m.ToggleStart == Undecided {
+		return Yes
+	}
+
+	return m.ToggleStart
+}
+
+// Value returns the current value as Yes, No or Undecided and the error. If
+// an error occurred, Undecided is returned.
+func (m *Model) Value() (Value, error) {
 	if m.Err != nil {
-		return false, m.Err
+		return Undecided, m.Err
 	}
 
-	if m.value == Undecided {
-		return false, fmt.Errorf("no decision was made")
+	return m.value, nil
+}
+
+// decision returns the current value as a bool and reports an error if no
+// decision was made.
+func (m *Model) decision() (bool, error) {
+	return decision(m.Result())
+}
+
+// yesLabel returns the configured YesLabel or the built-in text for Yes.
+func (m *Model) yesLabel() string {
+	return orDefault(m.YesLabel, promptkit.CurrentStrings().Yes)
+}
+
+// noLabel returns the configured NoLabel or the built-in text for No.
+func (m *Model) noLabel() string {
+	return orDefault(m.NoLabel, promptkit.CurrentStrings().No)
+}
+
+// acceleratorKey returns the first single-character key of the mapping in
+// lower case or the first character of the label if there is none.
+func acceleratorKey(mapping []string, label string) string {
+	for _, key := range mapping {
+		if utf8.RuneCountInString(key) == 1 {
+			return strings.ToLower(key)
+		}
+	}
+
+	for _, r := range label {
+		return strings.ToLower(string(r))
+	}
+
+	return ""
+}
+
+func orDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
 	}
 
-	return *m.value, m.Err
+	return value
 }
 
 func zeroAwareMin(a int, b int) int {
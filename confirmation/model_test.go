@@ -1,13 +1,25 @@
 package confirmation_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/bubbles/help"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/erikgeiser/promptkit"
 	"github.com/erikgeiser/promptkit/confirmation"
 	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/reflow/ansi"
 	"github.com/muesli/termenv"
 )
 
@@ -63,8 +75,8 @@ func TestDefaultUndecided(t *testing.T) {
 	}
 
 	v, err := m.Value()
-	if err == nil {
-		t.Errorf("getting value before deciding did not return an error but %v", v)
+	if err != nil || v != confirmation.Undecided {
+		t.Errorf("expected Undecided before deciding, got %v, %v", v, err)
 	}
 
 	test.AssertGoldenView(t, m, "default_undecided.golden")
@@ -86,13 +98,153 @@ func TestDefaultNilIsUndecided(t *testing.T) {
 	}
 
 	v, err := m.Value()
-	if err == nil {
-		t.Errorf("getting value before deciding did not return an error but %v", v)
+	if err != nil || v != confirmation.Undecided {
+		t.Errorf("expected Undecided before deciding, got %v, %v", v, err)
 	}
 
 	test.AssertGoldenView(t, m, "default_nil.golden")
 }
 
+func TestValue(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.Undecided)
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	value, err := m.Value()
+	if err != nil || value != confirmation.Undecided {
+		t.Errorf("expected Undecided without error, got %v, %v", value, err)
+	}
+
+	test.Update(t, m, test.KeyMsg('n'))
+
+	value, err = m.Value()
+	if err != nil || value != confirmation.No {
+		t.Errorf("expected No without error, got %v, %v", value, err)
+	}
+}
+
+func TestSubmitSelected(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		defaultValue confirmation.Value
+		keys         []tea.Msg
+		expected     bool
+	}{
+		{"default yes", confirmation.Yes, []tea.Msg{tea.KeyEnter}, true},
+		{"default no", confirmation.No, []tea.Msg{tea.KeyEnter}, false},
+		{"select no", confirmation.Yes, []tea.Msg{tea.KeyRight, tea.KeyEnter}, false},
+		{"toggle", confirmation.No, []tea.Msg{tea.KeyTab, tea.KeyEnter}, true},
+		{"undecided", confirmation.Undecided, []tea.Msg{tea.KeyEnter, tea.KeyLeft, tea.KeyEnter}, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := confirmation.New("ready?", testCase.defaultValue)
+			m := confirmation.NewModel(c)
+
+			test.Run(t, m, testCase.keys...)
+			assertNoError(t, m)
+
+			if getValue(t, m) != testCase.expected {
+				t.Errorf("expected %v, got %v", testCase.expected, !testCase.expected)
+			}
+		})
+	}
+}
+
+func TestSubmitSelectedKeyMap(t *testing.T) {
+	t.Parallel()
+
+	keyMap := confirmation.NewDefaultKeyMap()
+	if !reflect.DeepEqual(keyMap.SubmitSelected, []string{"enter"}) {
+		t.Errorf("unexpected default SubmitSelected keys %q", keyMap.SubmitSelected)
+	}
+
+	keyMap.SubmitSelected = []string{"s"}
+
+	c := confirmation.New("ready?", confirmation.Undecided)
+	c.KeyMap = keyMap
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m)
+
+	for _, key := range []tea.Msg{tea.KeyEnter, test.KeyMsg('s')} {
+		if cmd := test.Update(t, m, key); cmd != nil {
+			t.Fatalf("%v submitted an Undecided value", key)
+		}
+	}
+
+	test.Update(t, m, tea.KeyLeft)
+
+	cmd := test.Update(t, m, test.KeyMsg('s'))
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Fatalf("SubmitSelected key did not confirm the selected value")
+	}
+
+	if !getValue(t, m) {
+		t.Errorf("SubmitSelected key did not confirm yes")
+	}
+
+	keyMap = confirmation.NewDefaultKeyMap()
+	keyMap.Yes = nil
+	keyMap.No = nil
+	keyMap.SubmitSelected = nil
+
+	c = confirmation.New("ready?", confirmation.Yes)
+	c.KeyMap = keyMap
+	c.Input = strings.NewReader("")
+	c.Output = io.Discard
+
+	_, err := c.RunPrompt()
+	if err == nil || !strings.Contains(err.Error(), "no submit key") {
+		t.Errorf("key map without submit keys was accepted: %v", err)
+	}
+}
+
+func TestSelectionMethod(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		keys     []tea.Msg
+		expected string
+	}{
+		{[]tea.Msg{test.KeyMsg('y')}, "yes-key"},
+		{[]tea.Msg{test.KeyMsg('n')}, "no-key"},
+		{[]tea.Msg{tea.KeyEnter}, "default"},
+		{[]tea.Msg{tea.KeyTab, tea.KeyEnter}, "toggle"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.expected, func(t *testing.T) {
+			t.Parallel()
+
+			c := confirmation.New("ready?", confirmation.Yes)
+			c.ResultTemplate = `{{ .SelectionMethod }}`
+			m := confirmation.NewModel(c)
+
+			test.Run(t, m, testCase.keys...)
+			assertNoError(t, m)
+
+			if m.View() != testCase.expected {
+				t.Errorf("expected selection method %q, got %q", testCase.expected, m.View())
+			}
+		})
+	}
+}
+
 func TestImmediatelyChooseYes(t *testing.T) {
 	t.Parallel()
 
@@ -148,8 +300,8 @@ func TestToggle(t *testing.T) {
 	assertNoError(t, m)
 
 	v, err := m.Value()
-	if err == nil {
-		t.Fatalf("getting value before decision did not produce error but %v", v)
+	if err != nil || v != confirmation.Undecided {
+		t.Fatalf("expected Undecided before decision, got %v, %v", v, err)
 	}
 
 	test.AssertGoldenView(t, m, "toggle_before.golden")
@@ -218,6 +370,246 @@ func TestSelectNo(t *testing.T) {
 	test.AssertGoldenView(t, m, "select_no_confirmed.golden")
 }
 
+func TestVertical(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.Undecided)
+	c.Vertical = true
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m, tea.KeyDown)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "vertical_no.golden")
+
+	if getValue(t, m) {
+		t.Fatalf("key down did not select no")
+	}
+
+	test.Update(t, m, tea.KeyUp)
+	test.AssertGoldenView(t, m, "vertical_yes.golden")
+
+	if !getValue(t, m) {
+		t.Fatalf("key up did not select yes")
+	}
+}
+
+func TestGlyphs(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.Yes)
+	c.SelectedGlyph = "[x] "
+	c.UnselectedGlyph = "[ ] "
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "glyphs.golden")
+
+	view := test.StripANSI(m.View())
+	if view != "ready? [x] Yes [ ] No" {
+		t.Errorf("unexpected view %q", view)
+	}
+}
+
+func TestIcons(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.Yes)
+	c.Icon = promptkit.DefaultIcons.Question
+	c.ResultIcon = promptkit.DefaultIcons.Success
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "icons.golden")
+
+	test.Update(t, m, tea.KeyEnter)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "icons_result.golden")
+}
+
+func TestTheme(t *testing.T) {
+	t.Parallel()
+
+	theme := promptkit.Theme{PromptColor: "4", SelectedColor: "5", UnselectedColor: "8"}
+
+	c := confirmation.New("ready?", confirmation.Yes, theme)
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "theme.golden")
+
+	test.Update(t, m, tea.KeyEnter)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "theme_result.golden")
+}
+
+func TestEchoAnswer(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("Proceed?", confirmation.Undecided)
+	c.EchoAnswer = true
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m, test.KeyMsg('y'))
+	assertNoError(t, m)
+
+	if view := m.View(); view != "Proceed? Yes\n" {
+		t.Errorf("unexpected echoed answer: %q", view)
+	}
+}
+
+func TestMouse(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.Undecided)
+	c.EnableMouse = true
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	// the view is rendered as "ready?  Yes  No"
+	test.Run(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 13, Y: 0})
+	assertNoError(t, m)
+
+	if getValue(t, m) {
+		t.Fatalf("clicking No did not select no")
+	}
+
+	cmd := test.Update(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 9, Y: 0})
+	if cmd != nil {
+		t.Errorf("single click produced command %v", cmd)
+	}
+
+	if !getValue(t, m) {
+		t.Fatalf("clicking Yes did not select yes")
+	}
+
+	cmd = test.Update(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 9, Y: 0})
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Errorf("double click did not produce quit signal")
+	}
+}
+
+func TestMouseLabelInPrompt(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("Yes or No?", confirmation.Undecided)
+	c.EnableMouse = true
+	c.ColorProfile = termenv.Ascii
+	m := confirmation.NewModel(c)
+
+	// the view is rendered as "Yes or No?  Yes  No"
+	test.Run(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 1, Y: 0},
+		tea.MouseMsg{Type: tea.MouseLeft, X: 8, Y: 0})
+	assertNoError(t, m)
+
+	value, err := m.Value()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if value != confirmation.Undecided {
+		t.Fatalf("click on the prompt selected %v", value)
+	}
+
+	test.Update(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 13, Y: 0})
+
+	if !getValue(t, m) {
+		t.Errorf("clicking Yes did not select yes")
+	}
+
+	if strings.Contains(m.View(), "\x1b[820") {
+		t.Errorf("view contains hit-testing markers: %q", m.View())
+	}
+}
+
+func TestStateStore(t *testing.T) {
+	t.Parallel()
+
+	stores := map[string]confirmation.StateStore{
+		"memory": confirmation.NewMemoryStateStore(),
+		"file":   confirmation.NewFileStateStore(filepath.Join(t.TempDir(), "state.json")),
+	}
+
+	for name, store := range stores {
+		store := store
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := confirmation.New("ready?", confirmation.Yes)
+			c.StateStore = store
+			c.StateKey = "ready"
+			m := confirmation.NewModel(c)
+
+			test.Run(t, m, test.KeyMsg('n'))
+			assertNoError(t, m)
+
+			c = confirmation.New("ready?", confirmation.Yes)
+			c.StateStore = store
+			c.StateKey = "ready"
+			m = confirmation.NewModel(c)
+
+			test.Run(t, m)
+			assertNoError(t, m)
+
+			if getValue(t, m) {
+				t.Errorf("stored No was not used as default value")
+			}
+		})
+	}
+}
+
+func TestAuditWriter(t *testing.T) {
+	t.Parallel()
+
+	var audit bytes.Buffer
+
+	c := confirmation.New("ready?", confirmation.Yes)
+	c.AuditWriter = &audit
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m, tea.KeyEnter)
+	assertNoError(t, m)
+
+	var entry struct {
+		Time        string `json:"time"`
+		Prompt      string `json:"prompt"`
+		Answer      string `json:"answer"`
+		UsedDefault bool   `json:"usedDefault"`
+	}
+
+	err := json.Unmarshal(audit.Bytes(), &entry)
+	if err != nil {
+		t.Fatalf("unmarshal audit entry %q: %v", audit.String(), err)
+	}
+
+	_, err = time.Parse(time.RFC3339, entry.Time)
+	if err != nil {
+		t.Errorf("parse timestamp: %v", err)
+	}
+
+	if entry.Prompt != "ready?" || entry.Answer != "yes" || !entry.UsedDefault {
+		t.Errorf("unexpected audit entry %q", audit.String())
+	}
+
+	audit.Reset()
+
+	m = confirmation.NewModel(c)
+
+	test.Run(t, m, tea.KeyCtrlC)
+
+	if audit.Len() != 0 {
+		t.Errorf("aborted prompt was logged: %q", audit.String())
+	}
+}
+
 func TestAbort(t *testing.T) {
 	t.Parallel()
 
@@ -238,6 +630,23 @@ func TestAbort(t *testing.T) {
 	test.AssertGoldenView(t, m, "abort.golden")
 }
 
+func TestAbortEsc(t *testing.T) {
+	t.Parallel()
+
+	m := confirmation.NewModel(confirmation.New("ready?", confirmation.Yes))
+
+	test.Run(t, m, tea.KeyEsc)
+
+	value, err := m.Value()
+	if !errors.Is(err, promptkit.ErrAborted) {
+		t.Fatalf("esc produced %v instead of %q", err, promptkit.ErrAborted)
+	}
+
+	if value != confirmation.Undecided {
+		t.Errorf("aborted prompt reported %v", value)
+	}
+}
+
 func TestSubmit(t *testing.T) {
 	t.Parallel()
 
@@ -256,6 +665,29 @@ func TestSubmit(t *testing.T) {
 	test.AssertGoldenView(t, m, "submit.golden")
 }
 
+func TestTrimBlankLines(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.Yes)
+	c.Template = "\n\n{{ .Prompt }}\n\n"
+	c.ResultTemplate = "\n{{ .FinalValue }}\n\n"
+	c.TrimBlankLines = true
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	if view := m.View(); view != "ready?\n" {
+		t.Errorf("unexpected trimmed view: %q", view)
+	}
+
+	test.Update(t, m, tea.KeyEnter)
+
+	if view := m.View(); view != "true\n" {
+		t.Errorf("unexpected trimmed result view: %q", view)
+	}
+}
+
 func TestTemplateYN(t *testing.T) {
 	t.Parallel()
 
@@ -298,7 +730,11 @@ func getValue(tb testing.TB, m *confirmation.Model) bool {
 		tb.Fatalf("value: %v", err)
 	}
 
-	return v
+	if v == confirmation.Undecided {
+		tb.Fatalf("no decision was made")
+	}
+
+	return *v
 }
 
 func assertNoError(tb testing.TB, m *confirmation.Model) {
@@ -308,3 +744,1411 @@ func assertNoError(tb testing.TB, m *confirmation.Model) {
 		tb.Fatalf("model contains error: %v", m.Err)
 	}
 }
+
+func TestTemplates(t *testing.T) {
+	t.Parallel()
+
+	for name, tmpl := range confirmation.Templates {
+		resultTmpl, ok := confirmation.ResultTemplates[name]
+		if !ok {
+			t.Fatalf("no result template for template %q", name)
+		}
+
+		c := confirmation.New("ready?", confirmation.Yes)
+		c.Template = tmpl
+		c.ResultTemplate = resultTmpl
+		m := confirmation.NewModel(c)
+
+		test.Run(t, m, tea.KeyEnter)
+		assertNoError(t, m)
+
+		if test.StripANSI(m.View()) == "" {
+			t.Errorf("template %q rendered an empty result", name)
+		}
+	}
+}
+
+func TestDebugConfig(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.Yes)
+	c.Template = confirmation.TemplateYN
+
+	config := c.DebugConfig()
+
+	for _, expected := range []string{"Prompt: \"ready?\"\n", "DefaultValue: Yes\n", "Template: yn\n"} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("debug config does not contain %q:\n%s", expected, config)
+		}
+	}
+}
+
+func TestResultColors(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.Undecided)
+	c.NoColor = "1"
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m, test.KeyMsg('n'))
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "result_colors.golden")
+}
+
+func TestCommands(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.Undecided)
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+	commands := m.Commands()
+
+	go func() {
+		commands <- confirmation.SelectMsg{Value: confirmation.No}
+		commands <- confirmation.SelectMsg{Value: confirmation.Yes}
+		commands <- confirmation.SubmitMsg{}
+	}()
+
+	p := tea.NewProgram(m, tea.WithInput(nil), tea.WithOutput(io.Discard))
+
+	_, err := p.Run()
+	if err != nil {
+		t.Fatalf("running prompt: %v", err)
+	}
+
+	assertNoError(t, m)
+
+	value := getValue(t, m)
+	if !value {
+		t.Errorf("commands produced a No")
+	}
+}
+
+func TestCommandsOnChange(t *testing.T) {
+	t.Parallel()
+
+	var changes []confirmation.Value
+
+	c := confirmation.New("ready?", confirmation.Undecided)
+	c.OnChange = func(value confirmation.Value, _ any) {
+		changes = append(changes, value)
+	}
+	m := confirmation.NewModel(c)
+	commands := m.Commands()
+
+	go func() {
+		commands <- confirmation.SelectMsg{Value: confirmation.No}
+		commands <- confirmation.SelectMsg{Value: confirmation.Yes}
+		commands <- confirmation.SubmitMsg{}
+	}()
+
+	p := tea.NewProgram(m, tea.WithInput(nil), tea.WithOutput(io.Discard))
+
+	_, err := p.Run()
+	if err != nil {
+		t.Fatalf("running prompt: %v", err)
+	}
+
+	expected := []confirmation.Value{confirmation.No, confirmation.Yes}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("unexpected OnChange calls with %v, expected %v", changes, expected)
+	}
+}
+
+func TestRunPromptWithContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	c := confirmation.New("ready?", confirmation.Yes)
+	c.Input = nil
+	c.Output = io.Discard
+
+	_, err := c.RunPromptWithContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.No)
+	c.Timeout = 10 * time.Millisecond
+	c.Template = `{{ .RemainingSeconds }}`
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	batch, ok := m.Init()().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		t.Fatalf("init did not return a batch of commands")
+	}
+
+	assertNoError(t, m)
+
+	if m.View() != "1" {
+		t.Errorf("expected one remaining second, got %q", m.View())
+	}
+
+	test.Update(t, m, tea.KeyLeft)
+	test.Update(t, m, batch[len(batch)-1]())
+
+	if getValue(t, m) {
+		t.Errorf("timeout did not resolve to the default value")
+	}
+}
+
+func TestTimeoutDuringYesLockout(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.Yes)
+	c.Timeout = 10 * time.Millisecond
+	c.YesLockout = 50 * time.Millisecond
+	m := confirmation.NewModel(c)
+
+	batch, ok := m.Init()().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		t.Fatalf("init did not return a batch of commands")
+	}
+
+	cmd := test.Update(t, m, batch[len(batch)-1]())
+	if cmd == nil {
+		t.Fatalf("timeout during YesLockout was not postponed")
+	}
+
+	result, err := m.Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.TimedOut {
+		t.Fatalf("timeout confirmed Yes during YesLockout")
+	}
+
+	test.Update(t, m, cmd())
+
+	result, err = m.Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.TimedOut || !result.Value {
+		t.Errorf("unexpected result after YesLockout ended: %+v", result)
+	}
+}
+
+func TestTimeoutValidate(t *testing.T) {
+	t.Parallel()
+
+	errExplicitNo := errors.New("deleting everything requires an explicit no")
+
+	c := confirmation.New("Delete all data?", confirmation.No)
+	c.Timeout = 10 * time.Millisecond
+	c.Validate = func(v confirmation.Value) error {
+		if v == confirmation.No {
+			return errExplicitNo
+		}
+
+		return nil
+	}
+	c.ColorProfile = termenv.Ascii
+	m := confirmation.NewModel(c)
+
+	batch, ok := m.Init()().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		t.Fatalf("init did not return a batch of commands")
+	}
+
+	cmd := test.Update(t, m, batch[len(batch)-1]())
+	if cmd != nil {
+		t.Fatalf("timeout concluded with a value that does not pass validation")
+	}
+
+	assertNoError(t, m)
+
+	if !strings.Contains(m.View(), errExplicitNo.Error()) {
+		t.Errorf("validation error is not rendered after the timeout:\n%s", test.Indent(m.View()))
+	}
+
+	result, err := m.Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.TimedOut {
+		t.Errorf("rejected timeout is reported as timed out: %+v", result)
+	}
+}
+
+func TestTimeoutResult(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.No)
+	c.Timeout = 10 * time.Millisecond
+	m := confirmation.NewModel(c)
+
+	batch, ok := m.Init()().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		t.Fatalf("init did not return a batch of commands")
+	}
+
+	test.Update(t, m, batch[len(batch)-1]())
+
+	result, err := m.Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.TimedOut || result.RemainingOnAnswer != 0 || result.Value || result.Undecided {
+		t.Errorf("unexpected result after timeout: %+v", result)
+	}
+
+	c = confirmation.New("ready?", confirmation.No)
+	c.Timeout = time.Minute
+	m = confirmation.NewModel(c)
+
+	test.Run(t, m, test.KeyMsg('y'))
+
+	result, err = m.Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.TimedOut || !result.Value {
+		t.Errorf("unexpected result after answer: %+v", result)
+	}
+
+	if result.RemainingOnAnswer <= 0 || result.RemainingOnAnswer > time.Minute {
+		t.Errorf("unexpected remaining time %v", result.RemainingOnAnswer)
+	}
+}
+
+func TestTimeoutUndecided(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.Undecided)
+	c.Timeout = time.Second
+
+	if c.ValidateConfig() == nil {
+		t.Errorf("timeout without default value passed validation")
+	}
+
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m)
+
+	if m.Err == nil {
+		t.Errorf("timeout without default value did not produce an error")
+	}
+}
+
+func TestPreview(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("Apply these changes?", confirmation.Yes)
+	c.Preview = termenv.String("-old line that is long").Foreground(termenv.ANSIRed).String() + "\n" +
+		termenv.String("+new").Foreground(termenv.ANSIGreen).String() + "\n"
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 10, Height: 10})
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "preview.golden")
+
+	for _, line := range strings.Split(m.View(), "\n") {
+		if width := ansi.PrintableRuneWidth(line); width > 10 {
+			t.Errorf("line %q with width %d exceeds terminal width", line, width)
+		}
+	}
+
+	test.Update(t, m, tea.KeyEnter)
+
+	if strings.Contains(test.StripANSI(m.View()), "old") {
+		t.Errorf("preview is part of the result: %q", m.View())
+	}
+}
+
+func TestConfirmHold(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("delete everything?", confirmation.Yes)
+	c.ConfirmHold = time.Minute
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	cmd := test.Update(t, m, tea.KeyEnter)
+	if cmd == nil || cmd() == tea.Quit() {
+		t.Fatalf("first confirmation did not arm yes")
+	}
+
+	if !strings.Contains(m.View(), promptkit.CurrentStrings().ConfirmAgain) {
+		t.Errorf("armed state is not rendered: %q", m.View())
+	}
+
+	test.Update(t, m, tea.KeyRight)
+	test.Update(t, m, tea.KeyLeft)
+
+	cmd = test.Update(t, m, tea.KeyEnter)
+	if cmd == nil || cmd() == tea.Quit() {
+		t.Fatalf("changing the selection did not disarm yes")
+	}
+
+	cmd = test.Update(t, m, tea.KeyEnter)
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Errorf("second confirmation did not produce quit signal")
+	}
+
+	if !getValue(t, m) {
+		t.Errorf("confirmation did not conclude with yes")
+	}
+}
+
+func TestRunPromptHeadless(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		input        string
+		defaultValue confirmation.Value
+		expected     bool
+		expectErr    bool
+	}{
+		{name: "y", input: "y\n", defaultValue: confirmation.Undecided, expected: true},
+		{name: "yes", input: " YES\r\n", defaultValue: confirmation.No, expected: true},
+		{name: "n", input: "n\n", defaultValue: confirmation.Yes, expected: false},
+		{name: "no", input: "no", defaultValue: confirmation.Undecided, expected: false},
+		{name: "default", input: "\n", defaultValue: confirmation.Yes, expected: true},
+		{name: "empty", input: "", defaultValue: confirmation.No, expected: false},
+		{name: "unparseable default", input: "maybe\n", defaultValue: confirmation.Yes, expected: true},
+		{name: "unparseable", input: "maybe\n", defaultValue: confirmation.Undecided, expectErr: true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("create pipe: %v", err)
+			}
+
+			defer r.Close() //nolint:errcheck
+
+			_, err = io.WriteString(w, testCase.input)
+			if err != nil {
+				t.Fatalf("write input: %v", err)
+			}
+
+			w.Close() //nolint:errcheck,gosec
+
+			output := &bytes.Buffer{}
+
+			c := confirmation.New("ready?", testCase.defaultValue)
+			c.ColorProfile = termenv.Ascii
+			c.Input = r
+			c.Output = output
+
+			value, err := c.RunPrompt()
+
+			switch {
+			case testCase.expectErr && err == nil:
+				t.Fatalf("expected error for unparseable answer")
+			case testCase.expectErr:
+				return
+			case err != nil:
+				t.Fatalf("run prompt: %v", err)
+			}
+
+			if value != testCase.expected {
+				t.Errorf("expected %v, got %v", testCase.expected, value)
+			}
+
+			if !strings.Contains(output.String(), "ready?") {
+				t.Errorf("result was not rendered to the output: %q", output.String())
+			}
+		})
+	}
+}
+
+func TestRunPromptOutput(t *testing.T) { //nolint:paralleltest
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("create pipe: %v", err)
+	}
+
+	defer stdoutReader.Close() //nolint:errcheck
+
+	stdout := os.Stdout
+	os.Stdout = stdoutWriter
+
+	defer func() { os.Stdout = stdout }()
+
+	output := &bytes.Buffer{}
+
+	c := confirmation.New("ready?", confirmation.Undecided)
+	c.ColorProfile = termenv.Ascii
+	c.Input = strings.NewReader("y")
+	c.Output = output
+
+	value, err := c.RunPrompt()
+	if err != nil {
+		t.Fatalf("run prompt: %v", err)
+	}
+
+	os.Stdout = stdout
+
+	stdoutWriter.Close() //nolint:errcheck,gosec
+
+	leaked, err := io.ReadAll(stdoutReader)
+	if err != nil {
+		t.Fatalf("read stdout: %v", err)
+	}
+
+	if !value {
+		t.Errorf("expected yes")
+	}
+
+	if !strings.Contains(output.String(), "ready?") {
+		t.Errorf("confirmation was not rendered to the output: %q", output.String())
+	}
+
+	if len(leaked) != 0 {
+		t.Errorf("confirmation wrote to stdout: %q", leaked)
+	}
+}
+
+func TestYesLockout(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("delete everything?", confirmation.No)
+	c.YesLockout = time.Minute
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m, tea.KeyLeft, tea.KeyTab, test.KeyMsg('y'), confirmation.SelectMsg{Value: confirmation.Yes})
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "yes_lockout.golden")
+
+	if value, _ := m.Value(); value != confirmation.No {
+		t.Fatalf("yes could be selected during the lockout")
+	}
+
+	c = confirmation.New("delete everything?", confirmation.No)
+	c.YesLockout = time.Millisecond
+	m = confirmation.NewModel(c)
+
+	test.Run(t, m)
+	time.Sleep(5 * time.Millisecond)
+
+	cmd := test.Update(t, m, test.KeyMsg('y'))
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Errorf("yes key did not produce quit signal after the lockout")
+	}
+
+	if !getValue(t, m) {
+		t.Errorf("confirmation did not conclude with yes after the lockout")
+	}
+}
+
+func TestLabels(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("Fortfahren?", confirmation.Undecided)
+	c.YesLabel = "Ja"
+	c.NoLabel = "Nein"
+	c.KeyMap.Yes = []string{"j", "J"}
+	c.KeyMap.No = []string{"n", "N"}
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m, tea.KeyLeft)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "labels.golden")
+
+	c.Template = confirmation.TemplateYN
+	c.ResultTemplate = confirmation.ResultTemplateYN
+
+	test.Run(t, m, tea.KeyRight)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "labels_yn.golden")
+
+	cmd := test.Update(t, m, test.KeyMsg('j'))
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Fatalf("accelerator key did not produce quit signal")
+	}
+
+	if !getValue(t, m) {
+		t.Errorf("accelerator key did not select yes")
+	}
+
+	test.AssertGoldenView(t, m, "labels_yn_result.golden")
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	errExplicitNo := errors.New("deleting everything requires an explicit no")
+
+	c := confirmation.New("Delete all data?", confirmation.No)
+	c.Validate = func(v confirmation.Value) error {
+		if v == confirmation.No {
+			return errExplicitNo
+		}
+
+		return nil
+	}
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m)
+
+	cmd := test.Update(t, m, tea.KeyEnter)
+	if cmd != nil {
+		t.Fatalf("value that does not pass validation was confirmed")
+	}
+
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "validate.golden")
+
+	test.Update(t, m, tea.KeyLeft)
+
+	if strings.Contains(m.View(), errExplicitNo.Error()) {
+		t.Errorf("validation error is rendered for a different value")
+	}
+
+	cmd = test.Update(t, m, tea.KeyEnter)
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Errorf("valid value did not produce quit signal")
+	}
+
+	if !getValue(t, m) {
+		t.Errorf("confirmation did not conclude with yes")
+	}
+}
+
+func TestHangingIndent(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("Do you really want to delete all files in the current directory?", confirmation.No)
+	c.Icon = promptkit.DefaultIcons.Question
+	c.WrapMode = promptkit.HangingIndent(2)
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 20, Height: 10})
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "hanging_indent.golden")
+
+	for i, line := range strings.Split(m.View(), "\n") {
+		if width := ansi.PrintableRuneWidth(line); width > 20 {
+			t.Errorf("line %q with width %d exceeds terminal width", line, width)
+		}
+
+		if i > 0 && !strings.HasPrefix(line, "  ") {
+			t.Errorf("continuation line %q is not indented", line)
+		}
+	}
+}
+
+func TestWrapWidths(t *testing.T) {
+	t.Parallel()
+
+	for _, width := range []int{20, 40, 80} {
+		width := width
+
+		t.Run(fmt.Sprint(width), func(t *testing.T) {
+			t.Parallel()
+
+			c := confirmation.New("Do you really want to delete all files in the current directory?", confirmation.No)
+			c.Icon = promptkit.DefaultIcons.Question
+			c.ColorProfile = termenv.TrueColor
+			m := confirmation.NewModel(c)
+
+			test.Run(t, m, tea.WindowSizeMsg{Width: width, Height: 20})
+			assertNoError(t, m)
+			test.AssertGoldenView(t, m, fmt.Sprintf("wrap_%d.golden", width))
+
+			for i, line := range strings.Split(strings.TrimSuffix(m.View(), "\n"), "\n") {
+				if lineWidth := ansi.PrintableRuneWidth(line); lineWidth > width {
+					t.Errorf("line %q with width %d exceeds terminal width", line, lineWidth)
+				}
+
+				if i > 0 && !strings.HasPrefix(line, "  ") {
+					t.Errorf("continuation line %q is not indented under the prompt", line)
+				}
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("Do you want to continue with the installation?", confirmation.Yes)
+	c.ColorProfile = termenv.TrueColor
+
+	view, err := confirmation.Render(c, confirmation.No, 20)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 20, Height: 10}, tea.KeyRight)
+	assertNoError(t, m)
+
+	if view != m.View() {
+		t.Errorf("rendered view differs from the live view:\n%s\n%s", test.Indent(view), test.Indent(m.View()))
+	}
+
+	test.AssertGoldenView(t, m, "render.golden")
+}
+
+func TestRenderIgnoresStateStore(t *testing.T) {
+	t.Parallel()
+
+	store := confirmation.NewMemoryStateStore()
+
+	c := confirmation.New("ready?", confirmation.Yes)
+	c.StateStore = store
+	c.StateKey = "ready"
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m, test.KeyMsg('n'))
+	assertNoError(t, m)
+
+	c = confirmation.New("ready?", confirmation.Yes)
+	c.Template = confirmation.TemplateQuick
+	c.ResultTemplate = confirmation.ResultTemplateQuick
+	c.ColorProfile = termenv.Ascii
+	c.StateStore = store
+	c.StateKey = "ready"
+
+	view, err := confirmation.Render(c, confirmation.Yes, 0)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	c.StateStore = nil
+
+	expected, err := confirmation.Render(c, confirmation.Yes, 0)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	if view != expected {
+		t.Errorf("view depends on the StateStore:\n%s\n%s",
+			test.Indent(view), test.Indent(expected))
+	}
+
+	if !strings.Contains(view, "[Y/n]") {
+		t.Errorf("stored answer was used as default value:\n%s", test.Indent(view))
+	}
+}
+
+// sequence is a parent model that embeds managed confirmations and shows them
+// one after another.
+type sequence struct {
+	prompts []*confirmation.Model
+	current int
+	answers []confirmation.Value
+}
+
+func (s *sequence) Init() tea.Cmd {
+	return s.prompts[0].Init()
+}
+
+func (s *sequence) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if done, ok := msg.(confirmation.DoneMsg); ok {
+		s.answers = append(s.answers, done.Value)
+		s.current++
+
+		if s.current == len(s.prompts) {
+			return s, tea.Quit
+		}
+
+		return s, s.prompts[s.current].Init()
+	}
+
+	_, cmd := s.prompts[s.current].Update(msg)
+
+	return s, cmd
+}
+
+func (s *sequence) View() string {
+	return s.prompts[s.current].View()
+}
+
+func TestManagedSequence(t *testing.T) {
+	t.Parallel()
+
+	newPrompt := func(prompt string) *confirmation.Model {
+		c := confirmation.New(prompt, confirmation.Undecided)
+		c.Managed = true
+
+		return confirmation.NewModel(c)
+	}
+
+	s := &sequence{prompts: []*confirmation.Model{newPrompt("first?"), newPrompt("second?")}}
+
+	test.Run(t, s)
+
+	for _, key := range []tea.Msg{test.KeyMsg('y'), test.KeyMsg('n')} {
+		cmd := test.Update(t, s, key)
+		if cmd == nil {
+			t.Fatalf("managed confirmation did not report its conclusion")
+		}
+
+		msg := cmd()
+		if _, ok := msg.(confirmation.DoneMsg); !ok {
+			t.Fatalf("managed confirmation returned %T instead of a DoneMsg", msg)
+		}
+
+		cmd = test.Update(t, s, msg)
+		if len(s.answers) == len(s.prompts) && (cmd == nil || cmd() != tea.Quit()) {
+			t.Errorf("parent did not quit after the last confirmation")
+		}
+	}
+
+	if cmd := test.Update(t, s.prompts[0], test.KeyMsg('y')); cmd != nil {
+		t.Errorf("concluded confirmation still updates")
+	}
+
+	if !reflect.DeepEqual(s.answers, []confirmation.Value{confirmation.Yes, confirmation.No}) {
+		t.Errorf("unexpected answers %v", s.answers)
+	}
+}
+
+func TestHelp(t *testing.T) {
+	t.Parallel()
+
+	keyMap := confirmation.NewDefaultKeyMap()
+	keyMap.Interrupt = nil
+	keyMap.Descriptions.Submit = "accept"
+
+	var _ help.KeyMap = keyMap
+
+	var short []string
+	for _, b := range keyMap.ShortHelp() {
+		short = append(short, b.Help().Key+" "+b.Help().Desc)
+	}
+
+	expected := []string{"y/Y yes", "n/N no", "tab/space toggle", "enter accept"}
+	if !reflect.DeepEqual(short, expected) {
+		t.Errorf("unexpected short help %q, expected %q", short, expected)
+	}
+
+	full := keyMap.FullHelp()
+	if len(full) != 3 || len(full[2]) != 1 {
+		t.Fatalf("unexpected full help groups: %v", full)
+	}
+
+	if full[2][0].Help().Desc != "abort" {
+		t.Errorf("unexpected abort description %q", full[2][0].Help().Desc)
+	}
+
+	c := confirmation.New("ready?", confirmation.Yes)
+	c.KeyMap = keyMap
+	c.Template = `{{ range .Help }}{{ .Help.Key }}: {{ .Help.Desc }};{{ end }}`
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m)
+
+	view := test.StripANSI(m.View())
+	if view != "y/Y: yes;n/N: no;tab/space: toggle;enter: accept;" {
+		t.Errorf("unexpected view %q", view)
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	t.Parallel()
+
+	for input, expected := range map[string]confirmation.Value{
+		"yes": confirmation.Yes, " Y ": confirmation.Yes, "true": confirmation.Yes, "1": confirmation.Yes,
+		"no": confirmation.No, "N": confirmation.No, "FALSE": confirmation.No, "0": confirmation.No,
+	} {
+		value, err := confirmation.ParseValue(input)
+		if err != nil {
+			t.Errorf("parse %q: %v", input, err)
+		}
+
+		if value != expected {
+			t.Errorf("parsed %q as %v", input, value)
+		}
+	}
+
+	_, err := confirmation.ParseValue("maybe")
+	if err == nil {
+		t.Errorf("unrecognized value was accepted")
+	}
+}
+
+func TestDefaultValueFromEnv(t *testing.T) { //nolint:paralleltest
+	c := confirmation.New("ready?", confirmation.No)
+
+	err := c.DefaultValueFromEnv("PROMPTKIT_TEST_CONFIRM_DEFAULT")
+	if err != nil || c.DefaultValue != confirmation.No {
+		t.Errorf("unset variable changed the default value to %v: %v", c.DefaultValue, err)
+	}
+
+	t.Setenv("PROMPTKIT_TEST_CONFIRM_DEFAULT", "yes")
+
+	err = c.DefaultValueFromEnv("PROMPTKIT_TEST_CONFIRM_DEFAULT")
+	if err != nil || c.DefaultValue != confirmation.Yes {
+		t.Errorf("variable did not override the default value: %v, %v", c.DefaultValue, err)
+	}
+
+	t.Setenv("PROMPTKIT_TEST_CONFIRM_DEFAULT", "maybe")
+
+	err = c.DefaultValueFromEnv("PROMPTKIT_TEST_CONFIRM_DEFAULT")
+	if err == nil || c.DefaultValue != confirmation.Yes {
+		t.Errorf("invalid variable was not rejected: %v, %v", c.DefaultValue, err)
+	}
+}
+
+func TestFocusWraparound(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		toggleStart confirmation.Value
+		keys        []tea.Msg
+		expected    bool
+	}{
+		{"space", confirmation.Undecided, []tea.Msg{test.KeyMsg(' ')}, true},
+		{"space start no", confirmation.No, []tea.Msg{test.KeyMsg(' ')}, false},
+		{"tab twice", confirmation.Undecided, []tea.Msg{tea.KeyTab, tea.KeyTab}, false},
+		{"left", confirmation.Undecided, []tea.Msg{tea.KeyLeft}, true},
+		{"left wraps", confirmation.Undecided, []tea.Msg{tea.KeyLeft, tea.KeyLeft}, false},
+		{"right", confirmation.Undecided, []tea.Msg{tea.KeyRight}, false},
+		{"right wraps", confirmation.Undecided, []tea.Msg{tea.KeyRight, tea.KeyRight}, true},
+		{"mixed", confirmation.No, []tea.Msg{tea.KeyTab, tea.KeyRight, test.KeyMsg(' '), tea.KeyLeft}, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := confirmation.New("ready?", confirmation.Undecided)
+			c.ToggleStart = testCase.toggleStart
+			m := confirmation.NewModel(c)
+
+			test.Run(t, m, testCase.keys...)
+			assertNoError(t, m)
+
+			if getValue(t, m) != testCase.expected {
+				t.Errorf("expected %v, got %v", testCase.expected, !testCase.expected)
+			}
+		})
+	}
+}
+
+func TestForwardUnhandledKeys(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.Yes)
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m)
+
+	cmd := test.Update(t, m, tea.KeyF1)
+	if cmd != nil {
+		t.Fatalf("unhandled key was forwarded without ForwardUnhandledKeys")
+	}
+
+	c.ForwardUnhandledKeys = true
+
+	cmd = test.Update(t, m, tea.KeyF1)
+	if cmd == nil {
+		t.Fatalf("unhandled key was not forwarded")
+	}
+
+	expected := promptkit.UnhandledKeyMsg{Key: tea.KeyMsg{Type: tea.KeyF1}}
+	if msg := cmd(); !reflect.DeepEqual(msg, expected) {
+		t.Errorf("unexpected message %#v, expected %#v", msg, expected)
+	}
+
+	cmd = test.Update(t, m, tea.KeyTab)
+	if cmd != nil {
+		if _, ok := cmd().(promptkit.UnhandledKeyMsg); ok {
+			t.Errorf("handled key was forwarded")
+		}
+	}
+}
+
+func TestResultTemplateQuickDefault(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		defaultValue confirmation.Value
+		key          rune
+		expected     string
+	}{
+		{defaultValue: confirmation.Yes, key: 'n', expected: "Continue? [Y/n] No\n"},
+		{defaultValue: confirmation.No, key: 'y', expected: "Continue? [y/N] Yes\n"},
+		{defaultValue: confirmation.Undecided, key: 'y', expected: "Continue? [y/n] Yes\n"},
+	}
+
+	for _, testCase := range testCases {
+		c := confirmation.New("Continue?", testCase.defaultValue)
+		c.Template = confirmation.TemplateQuick
+		c.ResultTemplate = confirmation.ResultTemplateQuick
+		m := confirmation.NewModel(c)
+
+		test.Run(t, m, test.KeyMsg(testCase.key))
+		assertNoError(t, m)
+
+		if view := test.StripANSI(m.View()); view != testCase.expected {
+			t.Errorf("unexpected result view %q, expected %q", view, testCase.expected)
+		}
+	}
+}
+
+func TestQuick(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.NewQuick("Continue?", false)
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m, tea.KeyTab, tea.KeyLeft)
+	assertNoError(t, m)
+
+	if view := m.View(); view != "Continue? [y/N] " {
+		t.Errorf("unexpected view %q", view)
+	}
+
+	test.Update(t, m, tea.KeyEnter)
+
+	if getValue(t, m) {
+		t.Errorf("enter did not confirm the default value")
+	}
+
+	if view := test.StripANSI(m.View()); view != "Continue? [y/N] No\n" {
+		t.Errorf("unexpected result view %q", view)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("create pipe: %v", err)
+	}
+
+	defer r.Close() //nolint:errcheck
+
+	_, err = io.WriteString(w, "y\n")
+	if err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	w.Close() //nolint:errcheck,gosec
+
+	c = confirmation.NewQuick("Continue?", false)
+	c.Input = r
+	c.Output = &bytes.Buffer{}
+
+	value, err := c.RunPrompt()
+	if err != nil {
+		t.Fatalf("run headless: %v", err)
+	}
+
+	if !value {
+		t.Errorf("headless answer y was not confirmed")
+	}
+}
+
+func TestCallbackContext(t *testing.T) {
+	t.Parallel()
+
+	type operation struct{ ID string }
+
+	var (
+		changes []any
+		results []any
+		audit   bytes.Buffer
+	)
+
+	c := confirmation.New("deploy?", confirmation.No)
+	c.Context = operation{ID: "deploy-42"}
+	c.AuditWriter = &audit
+	c.OnChange = func(value confirmation.Value, context any) {
+		changes = append(changes, context)
+	}
+	c.OnResult = func(value confirmation.Value, context any) {
+		if value != confirmation.Yes {
+			t.Errorf("unexpected result value")
+		}
+
+		results = append(results, context)
+	}
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m, tea.KeyTab, tea.KeyUp, tea.KeyEnter)
+	assertNoError(t, m)
+
+	expected := []any{operation{ID: "deploy-42"}}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("unexpected OnChange calls with %v", changes)
+	}
+
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("unexpected OnResult calls with %v", results)
+	}
+
+	if !strings.Contains(audit.String(), `"context":{"ID":"deploy-42"}`) {
+		t.Errorf("context is missing in audit entry %q", audit.String())
+	}
+
+	c = confirmation.New("deploy?", confirmation.No)
+	c.OnResult = func(value confirmation.Value, context any) {
+		if context != nil {
+			t.Errorf("unexpected context %v", context)
+		}
+	}
+	m = confirmation.NewModel(c)
+
+	test.Run(t, m, tea.KeyEnter)
+	assertNoError(t, m)
+}
+
+func TestMultiLinePrompt(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("3 files changed\n2 files deleted\nApply?\n", confirmation.Yes)
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 16, Height: 10})
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "multi_line_prompt.golden")
+
+	lines := strings.Split(strings.TrimSuffix(test.StripANSI(m.View()), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 3 prompt lines and 1 option line, got:\n%s", test.Indent(m.View()))
+	}
+
+	if !strings.Contains(lines[3], "Yes") || !strings.Contains(lines[3], "No") {
+		t.Errorf("options are not rendered on their own line:\n%s", test.Indent(m.View()))
+	}
+
+	for _, line := range lines {
+		if width := ansi.PrintableRuneWidth(line); width > 16 {
+			t.Errorf("line %q with width %d exceeds terminal width", line, width)
+		}
+	}
+
+	test.Update(t, m, tea.KeyEnter)
+
+	expected := "3 files changed\n2 files deleted\nApply?\nYes\n"
+	if view := test.StripANSI(m.View()); view != expected {
+		t.Errorf("unexpected result view:\n%s\nexpected:\n%s", test.Indent(view), test.Indent(expected))
+	}
+}
+
+func TestRequired(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.Undecided)
+	c.Required = true
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	hint := fmt.Sprintf(promptkit.CurrentStrings().ChooseYesOrNo, "Yes", "No")
+	if strings.Contains(m.View(), hint) {
+		t.Errorf("hint is rendered before submitting")
+	}
+
+	cmd := test.Update(t, m, tea.KeyEnter)
+	if cmd != nil {
+		t.Fatalf("undecided value was submitted")
+	}
+
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "required.golden")
+
+	if !strings.Contains(test.StripANSI(m.View()), hint) {
+		t.Errorf("hint is not rendered after submitting undecided value:\n%s", test.Indent(m.View()))
+	}
+
+	test.Update(t, m, tea.KeyRight)
+
+	if strings.Contains(m.View(), hint) {
+		t.Errorf("hint is still rendered after choosing a value")
+	}
+
+	cmd = test.Update(t, m, tea.KeyEnter)
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Fatalf("decided value was not submitted")
+	}
+
+	if getValue(t, m) {
+		t.Errorf("unexpected value")
+	}
+
+	c = confirmation.New("ready?", confirmation.Undecided)
+	c.Required = true
+	m = confirmation.NewModel(c)
+
+	test.Run(t, m, tea.KeyEnter, tea.KeyEsc)
+
+	if _, err := m.Value(); !errors.Is(err, promptkit.ErrAborted) {
+		t.Errorf("required prompt could not be aborted: %v", err)
+	}
+}
+
+func TestRunPromptHeadlessLabels(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input    string
+		expected bool
+	}{
+		{input: "Ja\n", expected: true},
+		{input: "j\n", expected: true},
+		{input: "nein\n", expected: false},
+		{input: "1\n", expected: true},
+		{input: "false\n", expected: false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(strings.TrimSpace(testCase.input), func(t *testing.T) {
+			t.Parallel()
+
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("create pipe: %v", err)
+			}
+
+			defer r.Close() //nolint:errcheck
+
+			_, err = io.WriteString(w, testCase.input)
+			if err != nil {
+				t.Fatalf("write input: %v", err)
+			}
+
+			w.Close() //nolint:errcheck,gosec
+
+			c := confirmation.New("Weiter?", confirmation.Undecided)
+			c.YesLabel = "Ja"
+			c.NoLabel = "Nein"
+			c.KeyMap.Yes = []string{"j"}
+			c.ColorProfile = termenv.Ascii
+			c.Input = r
+			c.Output = &bytes.Buffer{}
+
+			value, err := c.RunPrompt()
+			if err != nil {
+				t.Fatalf("run prompt: %v", err)
+			}
+
+			if value != testCase.expected {
+				t.Errorf("expected %v for %q, got %v", testCase.expected, testCase.input, value)
+			}
+		})
+	}
+}
+
+func TestHeadlessLineEnding(t *testing.T) {
+	t.Parallel()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("create pipe: %v", err)
+	}
+
+	defer r.Close() //nolint:errcheck
+
+	_, err = io.WriteString(w, "y\n")
+	if err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	w.Close() //nolint:errcheck,gosec
+
+	output := &bytes.Buffer{}
+
+	c := confirmation.New("first line\nsecond line", confirmation.Undecided)
+	c.ColorProfile = termenv.Ascii
+	c.LineEnding = promptkit.CRLF
+	c.Input = r
+	c.Output = output
+
+	_, err = c.RunPrompt()
+	if err != nil {
+		t.Fatalf("run prompt: %v", err)
+	}
+
+	if !strings.Contains(output.String(), "\r\n") {
+		t.Fatalf("output does not use CRLF line endings: %q", output.String())
+	}
+
+	if strings.Count(output.String(), "\n") != strings.Count(output.String(), "\r\n") {
+		t.Errorf("output contains bare line feeds: %q", output.String())
+	}
+}
+
+func TestHeadlessOutput(t *testing.T) {
+	t.Parallel()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("create pipe: %v", err)
+	}
+
+	defer r.Close() //nolint:errcheck
+
+	_, err = io.WriteString(w, "y\n")
+	if err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	w.Close() //nolint:errcheck,gosec
+
+	output := &bytes.Buffer{}
+
+	c := confirmation.New("ready?", confirmation.Undecided)
+	c.ColorProfile = termenv.Ascii
+	c.Input = r
+	c.Output = output
+
+	_, err = c.RunPrompt()
+	if err != nil {
+		t.Fatalf("run prompt: %v", err)
+	}
+
+	expected := "ready? Yes\n"
+	if output.String() != expected {
+		t.Errorf("expected output %q, got %q", expected, output.String())
+	}
+}
+
+func TestProgramOptions(t *testing.T) {
+	t.Parallel()
+
+	output := &bytes.Buffer{}
+	override := &bytes.Buffer{}
+	applied := false
+
+	c := confirmation.New("ready?", confirmation.Undecided)
+	c.ColorProfile = termenv.Ascii
+	c.Input = strings.NewReader("y")
+	c.Output = output
+	c.ProgramOptions = []tea.ProgramOption{
+		tea.WithOutput(override),
+		func(*tea.Program) { applied = true },
+	}
+
+	_, err := c.RunPrompt()
+	if err != nil {
+		t.Fatalf("run prompt: %v", err)
+	}
+
+	if !applied {
+		t.Errorf("program option was not applied")
+	}
+
+	if output.Len() != 0 {
+		t.Errorf("program option did not override the output: %q", output.String())
+	}
+
+	if !strings.Contains(override.String(), "ready?") {
+		t.Errorf("confirmation was not rendered to the overridden output: %q", override.String())
+	}
+}
+
+func TestRunResultHeadless(t *testing.T) {
+	t.Parallel()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("create pipe: %v", err)
+	}
+
+	defer r.Close() //nolint:errcheck
+
+	_, err = io.WriteString(w, "\n")
+	if err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	w.Close() //nolint:errcheck,gosec
+
+	c := confirmation.New("ready?", confirmation.Yes)
+	c.ColorProfile = termenv.Ascii
+	c.Input = r
+	c.Output = &bytes.Buffer{}
+
+	result, err := c.RunResult()
+	if err != nil {
+		t.Fatalf("run prompt: %v", err)
+	}
+
+	if !result.Value || !result.UsedDefault || result.TimedOut {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestResultMetadata(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("ready?", confirmation.Yes)
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m, tea.KeyEnter)
+
+	result, err := m.Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Value || !result.UsedDefault || result.Aborted || result.Duration <= 0 {
+		t.Errorf("unexpected result after submitting the default: %+v", result)
+	}
+
+	var audit bytes.Buffer
+
+	c.AuditWriter = &audit
+	m = confirmation.NewModel(c)
+
+	test.Run(t, m, test.KeyMsg('y'))
+
+	result, err = m.Result()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Value || result.UsedDefault {
+		t.Errorf("unexpected result after explicit answer: %+v", result)
+	}
+
+	if !strings.Contains(audit.String(), `"usedDefault":false`) {
+		t.Errorf("audit entry disagrees with the result: %q", audit.String())
+	}
+
+	c.AuditWriter = nil
+
+	m = confirmation.NewModel(c)
+
+	test.Run(t, m, tea.KeyCtrlC)
+
+	result, err = m.Result()
+	if !errors.Is(err, promptkit.ErrAborted) {
+		t.Fatalf("unexpected error %v, expected %v", err, promptkit.ErrAborted)
+	}
+
+	if !result.Undecided || !result.Aborted || result.Duration <= 0 {
+		t.Errorf("unexpected result after abort: %+v", result)
+	}
+}
@@ -0,0 +1,78 @@
+package confirmation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Policy decides whether a confirmation prompt is actually shown or whether it
+// is answered in advance, for example based on a command line flag such as
+// --overwrite=ask|always|never. Policy implements flag.Value.
+type Policy int
+
+const (
+	// PolicyAsk shows the confirmation prompt.
+	PolicyAsk Policy = iota
+	// PolicyYes answers the confirmation with yes without prompting.
+	PolicyYes
+	// PolicyNo answers the confirmation with no without prompting.
+	PolicyNo
+)
+
+// ParsePolicy parses a policy from its string representation. PolicyAsk is
+// represented by "ask", PolicyYes by "yes" or "always" and PolicyNo by "no"
+// or "never". The input is not case-sensitive.
+func ParsePolicy(policy string) (Policy, error) {
+	switch strings.ToLower(policy) {
+	case "ask":
+		return PolicyAsk, nil
+	case "yes", "always":
+		return PolicyYes, nil
+	case "no", "never":
+		return PolicyNo, nil
+	default:
+		return PolicyAsk, fmt.Errorf("invalid policy %q, expected ask, yes, always, no or never", policy)
+	}
+}
+
+// String returns the string representation of the policy.
+func (p Policy) String() string {
+	switch p {
+	case PolicyAsk:
+		return "ask"
+	case PolicyYes:
+		return "yes"
+	case PolicyNo:
+		return "no"
+	default:
+		return fmt.Sprintf("Policy(%d)", int(p))
+	}
+}
+
+// Set parses the policy from a string as described in ParsePolicy.
+func (p *Policy) Set(value string) error {
+	policy, err := ParsePolicy(value)
+	if err != nil {
+		return err
+	}
+
+	*p = policy
+
+	return nil
+}
+
+// RunWithPolicy executes the confirmation prompt only if the policy is
+// PolicyAsk. Otherwise, the answer that is forced by the policy is returned
+// without prompting.
+func (c *Confirmation) RunWithPolicy(policy Policy) (bool, error) {
+	switch policy {
+	case PolicyAsk:
+		return c.RunPrompt()
+	case PolicyYes:
+		return true, nil
+	case PolicyNo:
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid policy %s", policy)
+	}
+}
@@ -0,0 +1,85 @@
+package confirmation_test
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/erikgeiser/promptkit/confirmation"
+)
+
+func TestRunWithPolicy(t *testing.T) {
+	t.Parallel()
+
+	c := confirmation.New("overwrite?", confirmation.Undecided)
+
+	for policy, expected := range map[confirmation.Policy]bool{
+		confirmation.PolicyYes: true,
+		confirmation.PolicyNo:  false,
+	} {
+		value, err := c.RunWithPolicy(policy)
+		if err != nil {
+			t.Fatalf("run with policy %s: %v", policy, err)
+		}
+
+		if value != expected {
+			t.Errorf("policy %s produced %v", policy, value)
+		}
+	}
+}
+
+func TestPolicyFlag(t *testing.T) {
+	t.Parallel()
+
+	var policy confirmation.Policy
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(&policy, "overwrite", "ask, always or never")
+
+	err := flags.Parse([]string{"--overwrite=never"})
+	if err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+
+	if policy != confirmation.PolicyNo {
+		t.Errorf("unexpected policy: %s, expected %s", policy, confirmation.PolicyNo)
+	}
+
+	_, err = confirmation.ParsePolicy("sometimes")
+	if err == nil {
+		t.Errorf("parsing invalid policy did not produce an error")
+	}
+}
+
+func TestRunBoundExplicitlySet(t *testing.T) {
+	t.Parallel()
+
+	force := false
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.BoolVar(&force, "force", false, "overwrite without asking")
+
+	err := flags.Parse([]string{"--force"})
+	if err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+
+	explicitlySet := false
+
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "force" {
+			explicitlySet = true
+		}
+	})
+
+	c := confirmation.New("overwrite?", confirmation.Undecided)
+	c.Bind(&force, explicitlySet)
+
+	value, err := c.RunBound()
+	if err != nil {
+		t.Fatalf("run bound: %v", err)
+	}
+
+	if !value {
+		t.Errorf("explicitly set flag was not honored")
+	}
+}
@@ -1,18 +1,23 @@
 /*
 Package confirmation implements prompt for a binary confirmation such as a
 yes/no question. It also offers customizable appreance and a customizable key
-map.
+map. For questions with more than two outcomes, the Choice prompt offers a list
+of named actions and the selection package offers a prompt to pick one of many
+choices with the same template conventions.
 */
 package confirmation
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"text/template"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/internal/headless"
 	"github.com/muesli/termenv"
 )
 
@@ -24,6 +29,22 @@ const (
 	// DefaultResultTemplate defines the default appearance with which the
 	// finale result of the prompt is presented.
 	DefaultResultTemplate = ResultTemplateArrow
+
+	// DefaultYesColor is the default color with which Yes is rendered by the
+	// built-in result templates.
+	DefaultYesColor = "32"
+
+	// DefaultNoColor is the default color with which No is rendered by the
+	// built-in result templates.
+	DefaultNoColor = "32"
+
+	// DefaultSelectedGlyph is the default glyph with which the built-in
+	// templates indicate the selected value.
+	DefaultSelectedGlyph = "▸"
+
+	// DefaultUnselectedGlyph is the default glyph with which the built-in
+	// templates indicate the values that are not selected.
+	DefaultUnselectedGlyph = " "
 )
 
 // Value is the value of the confirmation prompt which can be Undecided, Yes or
@@ -59,32 +80,165 @@ type Confirmation struct {
 	// Prompt holds the question.
 	Prompt string
 
+	// Icon is rendered in front of the prompt by the built-in templates, for
+	// example promptkit.DefaultIcons.Question. ResultIcon is rendered in front
+	// of the prompt by the built-in result templates, for example
+	// promptkit.DefaultIcons.Success. By default, no icons are rendered.
+	Icon       string
+	ResultIcon string
+
+	// Preview holds pre-rendered content such as a colored diff that is
+	// displayed above the prompt while it is active. It is hard-wrapped to the
+	// terminal width while preserving ANSI sequences such that the height of
+	// the view is known and the preview is cleared properly when the prompt
+	// concludes. It is not part of the result.
+	Preview string
+
 	// DefaultValue decides if a value should already be selected at startup. By
 	// default it is Undecided but it can be set to Yes (corresponds to true)
 	// and No (corresponds to false).
 	DefaultValue Value
 
+	// Validate is called with the value when the user attempts to confirm it.
+	// If it returns an error, the prompt stays open and the error is rendered
+	// by the built-in templates until the value is confirmed again. It is not
+	// called when the prompt resolves to the DefaultValue due to the Timeout.
+	// When the answer is read from a non-terminal input, RunPrompt returns the
+	// error instead. If Validate is nil, every value can be confirmed.
+	Validate func(Value) error
+
+	// Required makes the prompt insist on an answer: When the Submit keys are
+	// pressed while the value is Undecided, the prompt stays open and the
+	// built-in templates ask to choose Yes or No. The Abort and Interrupt
+	// keys still end the prompt. The state is available in the Template as
+	// ShowRequiredHint.
+	Required bool
+
+	// ConfirmHold requires Yes to be confirmed twice within the given duration,
+	// for example for destructive actions. The first confirmation with the
+	// Submit or Yes keys only arms Yes and the prompt concludes only if it is
+	// confirmed again before the window passes. Changing the selection disarms
+	// Yes. The state is available in the Template as Armed and ArmRemaining.
+	ConfirmHold time.Duration
+
+	// YesLockout prevents selecting and confirming Yes until the given duration
+	// has passed since the prompt started, for example for destructive actions
+	// in combination with a DefaultValue of No. Any attempt to select or
+	// confirm Yes is ignored during that time. The remaining time is available
+	// in the Template as LockoutSeconds.
+	YesLockout time.Duration
+
+	// Timeout resolves the prompt to the DefaultValue if it was not answered
+	// within the given duration. The remaining time is available in the
+	// Template as RemainingSeconds, for example to render "(auto-Yes in 3s)".
+	// A Timeout requires a DefaultValue other than Undecided, otherwise the
+	// prompt fails with an error. If the DefaultValue is Yes, the Timeout is
+	// extended until the YesLockout ended. When the Timeout expires, the
+	// DefaultValue is submitted like an answer, so the prompt stays open with
+	// the error if Validate rejects it and a Yes is only armed if ConfirmHold
+	// is set. RunResult reports whether the Timeout expired and how much time
+	// was left when the prompt was answered.
+	Timeout time.Duration
+
+	// StateStore and StateKey enable remembering the previous answer. If both
+	// are set, the answer that was previously stored under StateKey is used
+	// instead of DefaultValue and the final answer is stored under StateKey.
+	StateStore StateStore
+	StateKey   string
+
+	// AuditWriter receives a JSON line with the RFC3339 timestamp, the prompt,
+	// the answer and whether the answer is the default value each time the
+	// prompt concludes with an answer, for example for compliance logging.
+	// Aborted prompts are not logged. The audit log is independent of the
+	// ResultTemplate. If Context is set, it is included as well.
+	AuditWriter io.Writer
+
+	// Context holds arbitrary data that identifies the prompt, for example an
+	// operation ID, such that a single OnChange or OnResult handler can be
+	// shared by many prompts. It is passed to the callbacks as is and it is
+	// included in the audit log, so it should be serializable as JSON if an
+	// AuditWriter is configured. It may be nil.
+	Context any
+
+	// OnChange is called with the new value and the Context each time the
+	// selected value changes.
+	OnChange func(value Value, context any)
+
+	// OnResult is called with the final value and the Context when the prompt
+	// concludes with an answer. It is not called for aborted prompts.
+	OnResult func(value Value, context any)
+
 	// Template holds the display template. A custom template can be used to
 	// completely customize the appearance of the text input. If empty, the
 	// DefaultTemplate is used. The following variables and functions are
 	// available:
 	//
 	//  * Prompt string: The configured prompt.
+	//  * PromptLines []string: The lines of the prompt without trailing line
+	//    breaks. The built-in templates render the options on a separate line
+	//    if the prompt has multiple lines.
+	//  * Icon string: The configured Icon.
 	//  * YesSelected bool: Whether or not Yes is the currently selected
 	//    value.
 	//  * NoSelected bool: Whether or not No is the currently selected value.
+	//  * YesLabel string: The configured YesLabel or (Strings).Yes.
+	//  * NoLabel string: The configured NoLabel or (Strings).No.
+	//  * YesKey string: The lower case accelerator key for Yes, which is the
+	//    first single-character key of KeyMap.Yes.
+	//  * NoKey string: The lower case accelerator key for No, which is the
+	//    first single-character key of KeyMap.No.
 	//  * Undecided bool: Whether or not Undecided is the currently selected
 	//    value.
+	//  * ValidationError error: The error returned by Validate for the
+	//    currently selected value or nil.
+	//  * ErrorMessage string: The message of the ValidationError or an empty
+	//    string.
+	//  * ShowRequiredHint bool: Whether an Undecided value was submitted while
+	//    Required is set.
 	//  * DefaultYes bool: Whether or not Yes is confiured as default value.
 	//  * DefaultNo bool: Whether or not No is confiured as default value.
 	//  * DefaultUndecided bool: Whether or not Undecided is confiured as
 	//    default value.
+	//  * Armed bool: Whether Yes was confirmed once and has to be confirmed
+	//    again due to ConfirmHold.
+	//  * ArmRemaining time.Duration: The remaining window for the second
+	//    confirmation or 0 if Yes is not armed.
+	//  * LockoutSeconds int: The seconds until Yes becomes available due to
+	//    YesLockout or 0 if Yes is not locked.
+	//  * RemainingSeconds int: The seconds until the prompt resolves to the
+	//    default value due to the Timeout or 0 if no Timeout is configured.
+	//  * SelectedGlyph string: The configured SelectedGlyph.
+	//  * UnselectedGlyph string: The configured UnselectedGlyph.
 	//  * TerminalWidth int: The width of the terminal.
+	//  * Help []key.Binding: The bindings of KeyMap.ShortHelp, each providing
+	//    the keys with .Help.Key and the description with .Help.Desc.
 	//  * promptkit.UtilFuncMap: Handy helper functions.
 	//  * termenv TemplateFuncs (see https://github.com/muesli/termenv).
+	//  * The functions of the configured Theme, see
+	//    promptkit.Theme.TemplateFuncs.
 	//  * The functions specified in ExtendedTemplateFuncs.
 	Template string
 
+	// YesLabel and NoLabel are rendered for Yes and No by the built-in
+	// templates, for example to localize an individual prompt. If empty, the
+	// texts configured with promptkit.SetStrings are used. The Yes and No keys
+	// of the KeyMap should be changed accordingly, for example to "j" and "J"
+	// for the German "Ja", which the TemplateYN then renders as "[J/n]".
+	YesLabel string
+	NoLabel  string
+
+	// SelectedGlyph and UnselectedGlyph are rendered in front of the selected
+	// value and the other values by the arrow and vertical templates, for
+	// example "[x] " and "[ ] ". They should have the same width. If empty,
+	// DefaultSelectedGlyph and DefaultUnselectedGlyph are used.
+	SelectedGlyph   string
+	UnselectedGlyph string
+
+	// Vertical stacks Yes and No on separate lines by using TemplateVertical
+	// instead of the DefaultTemplate. It has no effect if a custom Template is
+	// configured.
+	Vertical bool
+
 	// ResultTemplate is rendered as soon as a input has been confirmed.
 	// It is intended to permanently indicate the result of the prompt when the
 	// input itself has disappeared. This template is only rendered in the Run()
@@ -94,17 +248,54 @@ type Confirmation struct {
 	//  * FinalValue bool: The final value of the confirmation.
 	//  * FinalValue string: The final value's string representation ("true"
 	//    or "false").
+	//  * YesLabel string: The configured YesLabel or (Strings).Yes.
+	//  * NoLabel string: The configured NoLabel or (Strings).No.
+	//  * YesKey string: The lower case accelerator key for Yes, which is the
+	//    first single-character key of KeyMap.Yes.
+	//  * NoKey string: The lower case accelerator key for No, which is the
+	//    first single-character key of KeyMap.No.
 	//  * Prompt string: The configured prompt.
+	//  * PromptLines []string: The lines of the prompt without trailing line
+	//    breaks. The built-in templates render the options on a separate line
+	//    if the prompt has multiple lines.
+	//  * ResultIcon string: The configured ResultIcon.
 	//  * DefaultYes bool: Whether or not Yes is confiured as default value.
 	//  * DefaultNo bool: Whether or not No is confiured as default value.
 	//  * DefaultUndecided bool: Whether or not Undecided is confiured as
 	//    default value.
+	//  * SelectionMethod string: How the final value was chosen: "yes-key"
+	//    or "no-key" if it was chosen with the Yes or No keys, "default" if
+	//    the default value was submitted without changing the selection or
+	//    due to the Timeout and "toggle" if the selection was changed before
+	//    submitting it, for example with the SelectYes, SelectNo or Toggle
+	//    keys or the mouse.
+	//  * YesColor string: The configured YesColor.
+	//  * NoColor string: The configured NoColor.
 	//  * TerminalWidth int: The width of the terminal.
 	//  * promptkit.UtilFuncMap: Handy helper functions.
 	//  * termenv TemplateFuncs (see https://github.com/muesli/termenv).
+	//  * The functions of the configured Theme, see
+	//    promptkit.Theme.TemplateFuncs.
 	//  * The functions specified in ExtendedTemplateFuncs.
 	ResultTemplate string
 
+	// Theme colors the prompt, the selected value, the other values and help
+	// texts in the built-in templates. The colors of an empty Theme leave the
+	// appearance of the templates unchanged.
+	Theme promptkit.Theme
+
+	// YesColor and NoColor are the colors with which the final value is
+	// rendered by the built-in result templates, for example "2" for green
+	// and "1" for red. They accept the same values as the Foreground template
+	// function. If empty, DefaultYesColor and DefaultNoColor are used.
+	YesColor string
+	NoColor  string
+
+	// EchoAnswer replaces the result with the prompt followed by the chosen
+	// answer such as "Proceed? Yes" without any styling. It takes precedence
+	// over the ResultTemplate which is ignored when EchoAnswer is set.
+	EchoAnswer bool
+
 	// ExtendedTemplateFuncs can be used to add additional functions to the
 	// evaluation scope of the templates.
 	ExtendedTemplateFuncs template.FuncMap
@@ -113,53 +304,230 @@ type Confirmation struct {
 	// By default, DefaultKeyMap is used.
 	KeyMap *KeyMap
 
+	// ToggleStart is the value that the Toggle keys select while the value is
+	// still Undecided. By default, Yes is selected first.
+	ToggleStart Value
+
 	// WrapMode decides which way the prompt view is wrapped if it does not fit
 	// the terminal. It can be a WrapMode provided by promptkit or a custom
-	// function. By default it is promptkit.WordWrap. It can also be nil which
-	// disables wrapping and likely causes output glitches.
+	// function. By default it is promptkit.PrefixHangingIndent such that wrapped
+	// lines stay aligned under the icon. It can also be nil which disables
+	// wrapping and likely causes output glitches.
 	WrapMode promptkit.WrapMode
 
+	// TrimBlankLines removes leading and trailing blank lines from the rendered
+	// view such that templates with surrounding whitespace do not produce stray
+	// empty lines. By default, the view is rendered unmodified.
+	TrimBlankLines bool
+
+	// AltScreen renders the prompt in the alternate screen buffer such that it
+	// does not scroll the terminal's scrollback. The result is printed to the
+	// normal screen after the prompt has concluded.
+	AltScreen bool
+
+	// EnableMouse enables mouse support. Clicking on Yes or No selects the
+	// corresponding value and double-clicking confirms it. Only the YesLabel
+	// and NoLabel template variables are clickable, which carry zero-width
+	// markers for hit-testing while EnableMouse is set, so custom templates
+	// should render them instead of comparing them. The mouse coordinates are
+	// interpreted relative to the top left corner of the view, so RunPrompt
+	// renders the prompt in the alternate screen buffer like with AltScreen
+	// and when the prompt is embedded as a widget, the mouse messages should
+	// be translated accordingly.
+	EnableMouse bool
+
+	// RecordKeys collects all key presses that are processed by the confirmation
+	// prompt if it is not nil. The recorded keys can be replayed with
+	// promptkit.ReplayKeys, for example to reproduce a reported bug.
+	RecordKeys *[]tea.KeyMsg
+
+	// InitialWidth is the terminal width that is assumed until the terminal
+	// reports its actual size. Together with Input, Output and ColorProfile,
+	// it allows serving the prompt over connections where the terminal cannot be
+	// detected, such as SSH sessions. If it is 0, the width is unknown until
+	// the first tea.WindowSizeMsg.
+	InitialWidth int
+
+	// Managed disables everything the prompt does to the terminal or the program
+	// beyond rendering its view such that it can be embedded in a parent bubbletea
+	// program that owns the terminal. When set, the model does not return tea.Quit
+	// when the prompt concludes or aborts but a command that emits a DoneMsg with
+	// the result such that the parent program can decide what to do next. The
+	// model stops updating afterwards and RunPrompt should not be used.
+	Managed bool
+
+	// ForwardUnhandledKeys makes the model emit a promptkit.UnhandledKeyMsg for
+	// key presses that match none of the bindings of the KeyMap such that a
+	// parent program that embeds the prompt can handle them. By default, such
+	// keys are ignored, which is what RunPrompt expects.
+	ForwardUnhandledKeys bool
+
+	// LineEnding is used for the line breaks of the output that is written
+	// outside of the bubbletea renderer, which are the result of a headless
+	// confirmation and the result that is printed after the AltScreen was left.
+	// Raw terminals that do not translate line feeds print such output like a
+	// staircase, which promptkit.CRLF avoids. By default, "\n" is used. The
+	// views rendered while the prompt is running always end their lines with
+	// "\r\n".
+	LineEnding string
+
+	// ProgramOptions are passed to tea.NewProgram when RunPrompt starts the
+	// interactive prompt, for example tea.WithoutSignalHandler. They are applied
+	// after the built-in options for Input, Output, AltScreen and EnableMouse
+	// such that they can override them.
+	ProgramOptions []tea.ProgramOption
+
 	// Output is the output writer, by default os.Stdout is used.
 	Output io.Writer
-	// Input is the input reader, by default, os.Stdin is used.
+	// Input is the input reader, by default, os.Stdin is used. If it is a file
+	// that is not a terminal, RunPrompt reads a single line as the answer
+	// without starting the interactive prompt.
 	Input io.Reader
 
 	// ColorProfile determines how colors are rendered. By default, the terminal
-	// is queried.
+	// is queried. A profile that is forced with promptkit.SetColorProfile takes
+	// precedence and a single prompt cannot opt out of it.
 	ColorProfile termenv.Profile
+
+	// boundValue and boundExplicitly are configured with Bind.
+	boundValue      *bool
+	boundExplicitly bool
 }
 
 // New creates a new text input. If the default value is nil it is equivalent to
-// Undecided. See the Confirmation properties for more documentation.
-func New(prompt string, defaultValue Value) *Confirmation {
-	return &Confirmation{
+// Undecided. Optionally, a theme can be passed which is used as the Theme of
+// the prompt and whose SelectedColor is also used as YesColor and NoColor. See
+// the Confirmation properties for more documentation.
+func New(prompt string, defaultValue Value, theme ...promptkit.Theme) *Confirmation {
+	c := &Confirmation{
 		Prompt:                prompt,
 		DefaultValue:          defaultValue,
 		Template:              DefaultTemplate,
 		ResultTemplate:        DefaultResultTemplate,
+		YesColor:              DefaultYesColor,
+		NoColor:               DefaultNoColor,
+		SelectedGlyph:         DefaultSelectedGlyph,
+		UnselectedGlyph:       DefaultUnselectedGlyph,
 		KeyMap:                NewDefaultKeyMap(),
 		ExtendedTemplateFuncs: template.FuncMap{},
-		WrapMode:              promptkit.Truncate,
+		WrapMode:              promptkit.PrefixHangingIndent,
 		Output:                os.Stdout,
 		Input:                 os.Stdin,
 	}
+
+	if len(theme) > 0 {
+		c.Theme = theme[0]
+		c.YesColor = orDefault(c.Theme.SelectedColor, DefaultYesColor)
+		c.NoColor = orDefault(c.Theme.SelectedColor, DefaultNoColor)
+	}
+
+	return c
 }
 
-// RunPrompt executes the confirmation prompt.
-func (c *Confirmation) RunPrompt() (bool, error) {
+// ValidateConfig checks the key map and the templates of the confirmation
+// prompt without running it.
+func (c *Confirmation) ValidateConfig() error {
 	err := validateKeyMap(c.KeyMap)
 	if err != nil {
-		return false, fmt.Errorf("insufficient key map: %w", err)
+		return fmt.Errorf("insufficient key map: %w", err)
 	}
 
 	m := NewModel(c)
 
-	p := tea.NewProgram(m, tea.WithOutput(c.Output), tea.WithInput(c.Input))
+	if c.Timeout > 0 && c.DefaultValue == Undecided && c.StateStore == nil {
+		return errTimeoutUndecided
+	}
+
+	_, err = m.initTemplate()
+	if err != nil {
+		return err
+	}
+
+	_, err = m.initResultTemplate()
+
+	return err
+}
+
+var _ promptkit.ConfigValidator = &Confirmation{}
+
+// RunPrompt executes the confirmation prompt. If the Input is a file that is not
+// a terminal, for example in CI, a single line is read as the answer instead.
+// It may be the YesLabel or NoLabel, one of the Yes or No keys of the KeyMap or
+// any value that is accepted by ParseValue and an empty or unrecognized line
+// resolves to the default value. In this case, the ResultTemplate is still
+// rendered to the Output.
+func (c *Confirmation) RunPrompt() (bool, error) {
+	return decision(c.RunPromptResult())
+}
+
+// RunPromptWithContext executes the confirmation prompt like RunPrompt but
+// stops it as soon as the context is cancelled, in which case the terminal is
+// restored and the error of the context is returned.
+func (c *Confirmation) RunPromptWithContext(ctx context.Context) (bool, error) {
+	return decision(c.runResult(ctx))
+}
+
+// RunPromptValue executes the confirmation prompt like RunPrompt but returns
+// Yes, No or Undecided instead of a bool such that a prompt that was never
+// answered can be distinguished from an explicit No.
+func (c *Confirmation) RunPromptValue() (Value, error) {
+	result, err := c.RunPromptResult()
+	if err != nil {
+		return Undecided, err
+	}
+
+	return result.value(), nil
+}
+
+// RunPromptResult executes the confirmation prompt like RunPrompt but also
+// reports how the prompt was resolved, for example how long it took, whether
+// the default value was used or whether it was aborted, see Result. RunPrompt
+// and RunPromptValue are based on it.
+func (c *Confirmation) RunPromptResult() (Result, error) {
+	return c.RunResult()
+}
+
+// RunResult executes the confirmation prompt like RunPromptValue but also
+// reports whether the prompt resolved due to the Timeout and how much time
+// was left on the countdown when it was answered. It is equivalent to
+// RunPromptResult.
+func (c *Confirmation) RunResult() (Result, error) {
+	return c.runResult(context.Background())
+}
+
+// decision converts the result of a prompt into a bool and reports an error if
+// no decision was made.
+func decision(result Result, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+
+	if result.Undecided {
+		return false, fmt.Errorf("no decision was made")
+	}
+
+	return result.Value, nil
+}
+
+func (c *Confirmation) runResult(ctx context.Context) (Result, error) {
+	err := validateKeyMap(c.KeyMap)
+	if err != nil {
+		return Result{}, fmt.Errorf("insufficient key map: %w", err)
+	}
+
+	if !headless.Interactive(c.Input) {
+		return c.runHeadless()
+	}
+
+	m := NewModel(c)
 
-	_, err = p.Run()
+	err = promptkit.Run(m, promptkit.WithOutput(c.Output), promptkit.WithInput(c.Input),
+		promptkit.WithAltScreen(c.AltScreen), promptkit.WithMouse(c.EnableMouse),
+		promptkit.WithContext(ctx), promptkit.WithLineEnding(c.LineEnding),
+		promptkit.WithProgramOptions(c.ProgramOptions...))
 	if err != nil {
-		return false, fmt.Errorf("running prompt: %w", err)
+		return Result{}, err
 	}
 
-	return m.Value()
+	return m.Result()
 }
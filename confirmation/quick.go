@@ -0,0 +1,29 @@
+package confirmation
+
+// Quick asks the question on a single line with a [y/N] or [Y/n] indicator
+// appended to the prompt and returns as soon as y, n or enter is pressed, in
+// which case the default value is returned. Like RunPrompt, it reads a single
+// line instead if the input is not a terminal.
+func Quick(prompt string, defaultYes bool) (bool, error) {
+	return NewQuick(prompt, defaultYes).RunPrompt()
+}
+
+// NewQuick creates the confirmation prompt that is used by Quick such that it
+// can be customized before running it.
+func NewQuick(prompt string, defaultYes bool) *Confirmation {
+	c := New(prompt, NewValue(defaultYes))
+	c.Template = TemplateQuick
+	c.ResultTemplate = ResultTemplateQuick
+	c.WrapMode = nil
+
+	defaults := NewDefaultKeyMap()
+	c.KeyMap = &KeyMap{
+		Yes:            defaults.Yes,
+		No:             defaults.No,
+		SubmitSelected: defaults.SubmitSelected,
+		Abort:          defaults.Abort,
+		Descriptions:   defaults.Descriptions,
+	}
+
+	return c
+}
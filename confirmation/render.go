@@ -0,0 +1,30 @@
+package confirmation
+
+// Render returns the view of the confirmation as it is rendered by the
+// Template when the given value is selected in a terminal of the given width,
+// for example for snapshot tests. If width is 0, the width is unknown as before
+// the first tea.WindowSizeMsg. Render neither reads from the Input nor writes
+// to the Output or the terminal and it ignores the StateStore such that the
+// view does not depend on previously stored answers.
+func Render(c *Confirmation, v Value, width int) (string, error) {
+	stateless := *c
+	stateless.StateStore = nil
+
+	m := NewModel(&stateless)
+
+	m.Init()
+
+	if m.Err != nil {
+		return "", m.Err
+	}
+
+	m.value = v
+	m.width = zeroAwareMin(width, m.MaxWidth)
+
+	view := m.View()
+	if m.Err != nil {
+		return "", m.Err
+	}
+
+	return view, nil
+}
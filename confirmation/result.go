@@ -0,0 +1,86 @@
+package confirmation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/erikgeiser/promptkit"
+)
+
+// Result describes how a confirmation prompt was resolved, for example for
+// telemetry:
+//
+//   - Value is true for Yes and false for No. If no decision was made,
+//     Undecided is true and Value is false.
+//   - Duration is the time from the start of the prompt until it concluded or
+//     was aborted.
+//   - UsedDefault reports whether the value was not chosen explicitly but is
+//     the default value, for example because the prompt was submitted right
+//     away or because the Timeout expired.
+//   - Aborted reports whether the prompt was aborted with one of the Abort or
+//     Interrupt keys, in which case the corresponding error is returned as
+//     well.
+//   - TimedOut reports whether the prompt resolved to the default value
+//     because the Timeout expired and RemainingOnAnswer holds the time that
+//     was left on the countdown when the prompt was answered. Without a
+//     Timeout, both are zero.
+type Result struct {
+	Value             bool
+	Undecided         bool
+	Duration          time.Duration
+	UsedDefault       bool
+	Aborted           bool
+	TimedOut          bool
+	RemainingOnAnswer time.Duration
+}
+
+// Result returns the current value together with the metadata that describes
+// how the prompt was resolved.
+func (m *Model) Result() (Result, error) {
+	value, err := m.Value()
+	if err != nil {
+		return Result{
+			Undecided: true,
+			Duration:  m.duration,
+			Aborted: errors.Is(err, promptkit.ErrAborted) ||
+				errors.Is(err, promptkit.ErrInterrupted),
+		}, err
+	}
+
+	return Result{
+		Value:             value == Yes,
+		Undecided:         value == Undecided,
+		Duration:          m.duration,
+		UsedDefault:       m.usedDefault(),
+		TimedOut:          m.timedOut,
+		RemainingOnAnswer: m.remainingOnAnswer,
+	}, nil
+}
+
+// value returns the Value of the result as Yes, No or Undecided.
+func (r Result) value() Value {
+	switch {
+	case r.Undecided:
+		return Undecided
+	case r.Value:
+		return Yes
+	default:
+		return No
+	}
+}
+
+// usedDefault reports whether the prompt concluded with the default value
+// without it being chosen explicitly.
+func (m *Model) usedDefault() bool {
+	return m.selectionMethod == selectionMethodDefault
+}
+
+// recordDuration records the time since Init when the prompt quits for the
+// first time.
+func (m *Model) recordDuration() {
+	if m.duration != 0 || m.startedAt.IsZero() {
+		return
+	}
+
+	m.duration = time.Since(m.startedAt)
+}
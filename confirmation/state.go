@@ -0,0 +1,152 @@
+package confirmation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+const (
+	stateYes = "yes"
+	stateNo  = "no"
+)
+
+// StateStore persists the answers of confirmation prompts between runs such
+// that the previous answer can be used as the default value. Get returns an
+// empty string if no value is stored for the key.
+type StateStore interface {
+	Get(key string) (string, error)
+	Set(key string, value string) error
+}
+
+// MemoryStateStore is a StateStore that keeps the state in memory. It is safe
+// for concurrent use.
+type MemoryStateStore struct {
+	mu    sync.Mutex
+	state map[string]string
+}
+
+var _ StateStore = &MemoryStateStore{}
+
+// NewMemoryStateStore creates an empty in-memory StateStore.
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{state: map[string]string{}}
+}
+
+// Get returns the value stored for the key.
+func (s *MemoryStateStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state[key], nil
+}
+
+// Set stores the value for the key.
+func (s *MemoryStateStore) Set(key string, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state[key] = value
+
+	return nil
+}
+
+// FileStateStore is a StateStore that keeps the state in a JSON file. The file
+// is created when the first value is stored. It is safe for concurrent use
+// within a single process.
+type FileStateStore struct {
+	// Path is the path of the JSON file.
+	Path string
+
+	mu sync.Mutex
+}
+
+var _ StateStore = &FileStateStore{}
+
+// NewFileStateStore creates a StateStore that is backed by the JSON file at
+// the given path.
+func NewFileStateStore(path string) *FileStateStore {
+	return &FileStateStore{Path: path}
+}
+
+// Get returns the value stored for the key.
+func (s *FileStateStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.read()
+	if err != nil {
+		return "", err
+	}
+
+	return state[key], nil
+}
+
+// Set stores the value for the key.
+func (s *FileStateStore) Set(key string, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	state[key] = value
+
+	content, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal state: %w", err)
+	}
+
+	err = os.WriteFile(s.Path, content, 0o600) //nolint:gomnd
+	if err != nil {
+		return fmt.Errorf("write state file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileStateStore) read() (map[string]string, error) {
+	state := map[string]string{}
+
+	content, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read state file: %w", err)
+	}
+
+	err = json.Unmarshal(content, &state)
+	if err != nil {
+		return nil, fmt.Errorf("parse state file: %w", err)
+	}
+
+	return state, nil
+}
+
+func valueFromState(state string) (Value, error) {
+	switch state {
+	case "":
+		return Undecided, nil
+	case stateYes:
+		return Yes, nil
+	case stateNo:
+		return No, nil
+	default:
+		return Undecided, fmt.Errorf("invalid state %q", state)
+	}
+}
+
+func stateFromValue(value Value) string {
+	switch value {
+	case Yes:
+		return stateYes
+	case No:
+		return stateNo
+	default:
+		return ""
+	}
+}
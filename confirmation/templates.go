@@ -1,47 +1,136 @@
 package confirmation
 
+import "github.com/erikgeiser/promptkit"
+
 // TemplateArrow is a template where the current choice is indicated by an
-// arrow.
+// arrow or the configured SelectedGlyph.
 const TemplateArrow = `
-{{- Bold .Prompt -}}
+{{- if .Icon }}{{ print .Icon " " }}{{ end -}}
+{{- range $i, $line := .PromptLines }}{{ if $i }}{{ "\n" }}{{ end }}{{ Bold (ThemePrompt $line) }}{{ end -}}
+{{- if gt (len .PromptLines) 1 }}{{ "\n" }}{{ end -}}
 {{ if .YesSelected -}}
-	{{- print (Bold " ▸Yes ") " No" -}}
+	{{- print (Bold (ThemeSelected (print " " .SelectedGlyph .YesLabel " "))) .UnselectedGlyph (ThemeUnselected .NoLabel) -}}
 {{- else if .NoSelected -}}
-	{{- print "  Yes " (Bold "▸No") -}}
+	{{- print " " .UnselectedGlyph (ThemeUnselected .YesLabel) " " (Bold (ThemeSelected (print .SelectedGlyph .NoLabel))) -}}
 {{- else -}}
-	{{- "  Yes  No" -}}
+	{{- print " " .UnselectedGlyph (ThemeUnselected .YesLabel) " " .UnselectedGlyph (ThemeUnselected .NoLabel) -}}
 {{- end -}}
+{{- if .Armed }} {{ ThemeHelp (Strings).ConfirmAgain }}{{ end -}}
+{{- if .LockoutSeconds }} {{ ThemeHelp (printf (Strings).YesAvailableIn .LockoutSeconds) }}{{ end -}}
+{{- if .ShowRequiredHint }} {{ ThemeHelp (printf (Strings).ChooseYesOrNo .YesLabel .NoLabel) }}{{ end -}}
+{{- if .ErrorMessage }}{{ print "\n" (ThemeError (print "✘ " .ErrorMessage)) }}{{ end -}}
 `
 
 // ResultTemplateArrow is the ResultTemplate that matches TemplateArrow.
 const ResultTemplateArrow = `
-{{- print .Prompt " " -}}
+{{- if .ResultIcon }}{{ print .ResultIcon " " }}{{ end -}}
+{{- range $i, $line := .PromptLines }}{{ if $i }}{{ "\n" }}{{ end }}{{ $line }}{{ end -}}
+{{- if gt (len .PromptLines) 1 }}{{ "\n" }}{{ else }}{{ " " }}{{ end -}}
 {{- if .FinalValue -}}
-	{{- Foreground "32" "Yes" -}}
+	{{- Foreground .YesColor .YesLabel -}}
 {{- else -}}
-	{{- Foreground "32" "No" -}}
+	{{- Foreground .NoColor .NoLabel -}}
 {{- end }}
 `
 
+// TemplateVertical is a template where Yes and No are stacked on separate lines
+// and the current choice is indicated by an arrow or the configured
+// SelectedGlyph. It is used by default when Vertical is set.
+const TemplateVertical = `
+{{- if .Icon }}{{ print .Icon " " }}{{ end -}}
+{{- range $i, $line := .PromptLines }}{{ if $i }}{{ "\n" }}{{ end }}{{ Bold (ThemePrompt $line) }}{{ end }}
+{{ if .YesSelected -}}
+	{{- print (Bold (ThemeSelected (print .SelectedGlyph " " .YesLabel))) "\n" .UnselectedGlyph " " (ThemeUnselected .NoLabel) -}}
+{{- else if .NoSelected -}}
+	{{- print .UnselectedGlyph " " (ThemeUnselected .YesLabel) "\n" (Bold (ThemeSelected (print .SelectedGlyph " " .NoLabel))) -}}
+{{- else -}}
+	{{- print .UnselectedGlyph " " (ThemeUnselected .YesLabel) "\n" .UnselectedGlyph " " (ThemeUnselected .NoLabel) -}}
+{{- end -}}
+{{- if .Armed }} {{ ThemeHelp (Strings).ConfirmAgain }}{{ end -}}
+{{- if .LockoutSeconds }} {{ ThemeHelp (printf (Strings).YesAvailableIn .LockoutSeconds) }}{{ end -}}
+{{- if .ShowRequiredHint }} {{ ThemeHelp (printf (Strings).ChooseYesOrNo .YesLabel .NoLabel) }}{{ end -}}
+{{- if .ErrorMessage }}{{ print "\n" (ThemeError (print "✘ " .ErrorMessage)) }}{{ end -}}
+`
+
 // TemplateYN is a classic template with ja [yn] indicator where the current
 // value is capitalized and bold.
 const TemplateYN = `
-{{- Bold .Prompt -}}
+{{- if .Icon }}{{ print .Icon " " }}{{ end -}}
+{{- range $i, $line := .PromptLines }}{{ if $i }}{{ "\n" }}{{ end }}{{ Bold (ThemePrompt $line) }}{{ end -}}
+{{- if gt (len .PromptLines) 1 }}{{ "\n" }}{{ end -}}
 {{ if .YesSelected -}}
-	{{- print " [" (Bold "Y") "/n]" -}}
+	{{- print " [" (Bold (ThemeSelected (Upper .YesKey))) "/" .NoKey "]" -}}
 {{- else if .NoSelected -}}
-	{{- print " [y/" (Bold "N") "]" -}}
+	{{- print " [" .YesKey "/" (Bold (ThemeSelected (Upper .NoKey))) "]" -}}
 {{- else -}}
-	{{- " [y/n]" -}}
+	{{- print " [" .YesKey "/" .NoKey "]" -}}
 {{- end -}}
+{{- if .Armed }} {{ ThemeHelp (Strings).ConfirmAgain }}{{ end -}}
+{{- if .LockoutSeconds }} {{ ThemeHelp (printf (Strings).YesAvailableIn .LockoutSeconds) }}{{ end -}}
+{{- if .ShowRequiredHint }} {{ ThemeHelp (printf (Strings).ChooseYesOrNo .YesLabel .NoLabel) }}{{ end -}}
+{{- if .ErrorMessage }}{{ print "\n" (ThemeError (print "✘ " .ErrorMessage)) }}{{ end -}}
 `
 
 // ResultTemplateYN is the ResultTemplate that matches TemplateYN.
 const ResultTemplateYN = `
-{{- .Prompt -}}
+{{- if .ResultIcon }}{{ print .ResultIcon " " }}{{ end -}}
+{{- range $i, $line := .PromptLines }}{{ if $i }}{{ "\n" }}{{ end }}{{ $line }}{{ end -}}
+{{- if gt (len .PromptLines) 1 }}{{ "\n" }}{{ end -}}
 {{ if .FinalValue -}}
-	{{- print " [" (Foreground "32" (Bold "Y")) "/n]" -}}
+	{{- print " [" (Foreground .YesColor (Bold (Upper .YesKey))) "/" .NoKey "]" -}}
 {{- else -}}
-	{{- print " [y/" (Foreground "32" (Bold "N")) "]" -}}
+	{{- print " [" .YesKey "/" (Foreground .NoColor (Bold (Upper .NoKey))) "]" -}}
 {{- end }}
 `
+
+// TemplateQuick is a compact single-line template that appends a [y/N]
+// indicator with the default value capitalized to the prompt. It is used by
+// Quick and does not reflect changes of the selection.
+const TemplateQuick = `
+{{- print .Prompt " " -}}
+{{- if .DefaultYes -}}
+	{{- print "[" (Upper .YesKey) "/" .NoKey "] " -}}
+{{- else if .DefaultNo -}}
+	{{- print "[" .YesKey "/" (Upper .NoKey) "] " -}}
+{{- else -}}
+	{{- print "[" .YesKey "/" .NoKey "] " -}}
+{{- end -}}
+`
+
+// ResultTemplateQuick is the ResultTemplate that matches TemplateQuick.
+const ResultTemplateQuick = `
+{{- print .Prompt " " -}}
+{{- if .DefaultYes -}}
+	{{- print "[" (Upper .YesKey) "/" .NoKey "] " -}}
+{{- else if .DefaultNo -}}
+	{{- print "[" .YesKey "/" (Upper .NoKey) "] " -}}
+{{- else -}}
+	{{- print "[" .YesKey "/" .NoKey "] " -}}
+{{- end -}}
+{{- if .FinalValue }}{{ .YesLabel }}{{ else }}{{ .NoLabel }}{{ end }}
+`
+
+// defaultTheme holds the colors of the built-in templates which are used for
+// the empty colors of the Theme.
+var defaultTheme = promptkit.Theme{ErrorColor: "1"}
+
+// Templates holds all built-in templates by name such that they can be
+// selected by a string, for example from a configuration file. The matching
+// result templates are stored under the same name in ResultTemplates.
+var Templates = map[string]string{
+	"default":  DefaultTemplate,
+	"arrow":    TemplateArrow,
+	"vertical": TemplateVertical,
+	"yn":       TemplateYN,
+	"quick":    TemplateQuick,
+}
+
+// ResultTemplates holds all built-in result templates by name. The names
+// correspond to the templates in Templates.
+var ResultTemplates = map[string]string{
+	"default":  DefaultResultTemplate,
+	"arrow":    ResultTemplateArrow,
+	"vertical": ResultTemplateArrow,
+	"yn":       ResultTemplateYN,
+	"quick":    ResultTemplateQuick,
+}
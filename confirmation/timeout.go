@@ -0,0 +1,64 @@
+package confirmation
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// errTimeoutUndecided is returned if a Timeout is configured without a default
+// value to which the prompt could resolve.
+var errTimeoutUndecided = errors.New("timeout requires a default value")
+
+// countdownMsg is sent periodically while a Timeout is configured to update
+// the countdown and to resolve the prompt when the deadline is reached.
+type countdownMsg struct{}
+
+// countdown returns a command that sends the next countdownMsg at the next
+// full second of the remaining time or at the deadline.
+func (m *Model) countdown() tea.Cmd {
+	remaining := time.Until(m.deadline)
+
+	next := remaining % time.Second
+	if next <= 0 {
+		next = time.Second
+	}
+
+	if remaining < next {
+		next = remaining
+	}
+
+	return tea.Tick(next, func(time.Time) tea.Msg {
+		return countdownMsg{}
+	})
+}
+
+// remainingSeconds returns the number of seconds until the prompt resolves to
+// the default value, rounded up, or 0 if no Timeout is configured.
+func (m *Model) remainingSeconds() int {
+	if m.deadline.IsZero() {
+		return 0
+	}
+
+	remaining := time.Until(m.deadline).Seconds()
+	if remaining <= 0 {
+		return 0
+	}
+
+	return int(math.Ceil(remaining))
+}
+
+// recordRemaining stores the time that was left on the countdown when the
+// prompt was answered.
+func (m *Model) recordRemaining() {
+	if m.deadline.IsZero() || m.timedOut {
+		return
+	}
+
+	m.remainingOnAnswer = time.Until(m.deadline)
+	if m.remainingOnAnswer < 0 {
+		m.remainingOnAnswer = 0
+	}
+}
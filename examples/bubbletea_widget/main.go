@@ -26,6 +26,7 @@ var _ tea.Model = &shoppingCart{}
 func (s *shoppingCart) Init() tea.Cmd {
 	sel := selection.New("Add Items to Your Shopping Cart:", s.availableItems)
 	sel.Filter = nil
+	sel.Managed = true
 
 	s.selection = selection.NewModel(sel)
 
@@ -48,7 +49,7 @@ func (s *shoppingCart) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		s.addedItems[c]++
-	case keyMsg.String() == "esc":
+	case keyMsg.String() == "esc", keyMsg.String() == "ctrl+c":
 		return s, tea.Quit
 	default:
 		_, cmd := s.selection.Update(msg)
@@ -0,0 +1,24 @@
+// Package main demonstrates how promptkit/confirmation is used with more than
+// two named actions.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/erikgeiser/promptkit/confirmation"
+)
+
+func main() {
+	input := confirmation.NewChoice("The file already exists:", "Overwrite", "Skip", "Cancel")
+
+	action, err := input.RunPrompt()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+
+		os.Exit(1)
+	}
+
+	// do something with the result
+	_ = action
+}
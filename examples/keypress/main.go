@@ -0,0 +1,23 @@
+// Package main demonstrates how promptkit/keypress is used.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/erikgeiser/promptkit/keypress"
+)
+
+func main() {
+	input := keypress.New("Continue, skip or quit?", 'c', 's', 'q')
+
+	pressed, err := input.RunPrompt()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+
+		os.Exit(1)
+	}
+
+	// do something with the result
+	_ = pressed
+}
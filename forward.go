@@ -0,0 +1,19 @@
+package promptkit
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// UnhandledKeyMsg is emitted by the prompts for key presses that matched none
+// of their key bindings and were not consumed as text input if their
+// ForwardUnhandledKeys option is enabled. It allows a parent program that
+// embeds a prompt to handle these keys itself.
+type UnhandledKeyMsg struct {
+	Key tea.KeyMsg
+}
+
+// ForwardKey returns a command that emits an UnhandledKeyMsg for the key. It
+// is used by the prompts to implement their ForwardUnhandledKeys option.
+func ForwardKey(key tea.KeyMsg) tea.Cmd {
+	return func() tea.Msg {
+		return UnhandledKeyMsg{Key: key}
+	}
+}
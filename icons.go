@@ -0,0 +1,22 @@
+package promptkit
+
+// Icons is a set of leading glyphs that can be assigned to the Icon and
+// ResultIcon fields of the prompts for a consistent look.
+type Icons struct {
+	// Question is intended for the Icon of the prompts.
+	Question string
+	// Success is intended for the ResultIcon of the prompts.
+	Success string
+	// Failure is intended for results that indicate a failure, for example in
+	// custom result templates.
+	Failure string
+}
+
+// DefaultIcons is the built-in icon set. The prompts do not render any icons
+// unless their Icon or ResultIcon fields are set, for example to the icons of
+// DefaultIcons.
+var DefaultIcons = Icons{
+	Question: "?",
+	Success:  "✓",
+	Failure:  "✗",
+}
@@ -0,0 +1,38 @@
+// Package headless contains the input handling that is shared by the prompts
+// that can run without a terminal.
+package headless
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Interactive returns false if the input is a file such as os.Stdin that is
+// not connected to a terminal, for example in CI or because it is a pipe.
+// Other readers are assumed to provide interactive key input.
+func Interactive(input io.Reader) bool {
+	f, ok := input.(*os.File)
+	if !ok {
+		return true
+	}
+
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// ReadLine reads a single line without the line ending from the reader. A
+// missing line ending at the end of the input is tolerated such that empty
+// input results in an empty line.
+func ReadLine(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("reading input: %w", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
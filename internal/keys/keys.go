@@ -0,0 +1,31 @@
+// Package keys contains the key handling that is shared by the prompts.
+package keys
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Record appends the key to keys if keys is not nil. It is used by the prompts
+// to implement their RecordKeys option.
+func Record(keys *[]tea.KeyMsg, key tea.KeyMsg) {
+	if keys == nil {
+		return
+	}
+
+	*keys = append(*keys, key)
+}
+
+// HandledByInput returns true if the key is typed into or handled by a bubbles
+// text input with the given key map.
+func HandledByInput(km textinput.KeyMap, msg tea.KeyMsg) bool {
+	if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+		return true
+	}
+
+	return key.Matches(msg, km.CharacterForward, km.CharacterBackward, km.WordForward,
+		km.WordBackward, km.DeleteWordBackward, km.DeleteWordForward, km.DeleteAfterCursor,
+		km.DeleteBeforeCursor, km.DeleteCharacterBackward, km.DeleteCharacterForward,
+		km.LineStart, km.LineEnd, km.Paste)
+}
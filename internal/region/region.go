@@ -0,0 +1,194 @@
+// Package region records where parts of a rendered view end up on the screen
+// such that mouse clicks can be hit-tested against them. Parts are wrapped in
+// zero-width markers while the view is rendered and the markers are removed
+// again after the view was wrapped, which yields the final positions.
+package region
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/reflow/ansi"
+)
+
+// the markers are formatted like ANSI sequences such that all ANSI-aware width
+// calculations and wrap modes treat them as zero-width
+const (
+	startMarker = "\x1b[8200;"
+	endMarker   = "\x1b[8201;"
+	terminator  = 'y'
+)
+
+// Region is the area of the screen that a marked part of the view occupies. It
+// starts at Col in Row and ends before EndCol in EndRow.
+type Region struct {
+	ID     int
+	Row    int
+	Col    int
+	EndRow int
+	EndCol int
+}
+
+// Contains returns whether the cell at column x in row y is part of the region.
+func (r Region) Contains(x int, y int) bool {
+	switch {
+	case y < r.Row || y > r.EndRow:
+		return false
+	case y == r.Row && x < r.Col:
+		return false
+	case y == r.EndRow && x >= r.EndCol:
+		return false
+	default:
+		return true
+	}
+}
+
+// ContainsRow returns whether the region occupies at least a part of row y.
+func (r Region) ContainsRow(y int) bool {
+	return y >= r.Row && y <= r.EndRow
+}
+
+// Mark wraps the text in markers for the region with the given ID.
+func Mark(id int, text string) string {
+	suffix := strconv.Itoa(id) + string(terminator)
+
+	return startMarker + suffix + text + endMarker + suffix
+}
+
+// Extract removes all markers from the view and returns the regions of the
+// marked parts. If the end marker of a part was cut off, for example by a wrap
+// mode that truncates lines without using Preserve, the region ends at the
+// end of its first row.
+func Extract(view string) (string, []Region) {
+	if !strings.Contains(view, startMarker) {
+		return view, nil
+	}
+
+	var (
+		clean     strings.Builder
+		regions   []Region
+		rowWidths []int
+		open      = map[int]int{}
+	)
+
+	row, col := 0, 0
+
+	for i := 0; i < len(view); {
+		if id, n, ok := parseMarker(view[i:], startMarker); ok {
+			// wrap modes may repeat the last sequence after a line break
+			if _, found := open[id]; !found {
+				open[id] = len(regions)
+				regions = append(regions, Region{ID: id, Row: row, Col: col})
+			}
+
+			i += n
+
+			continue
+		}
+
+		if id, n, ok := parseMarker(view[i:], endMarker); ok {
+			if idx, found := open[id]; found {
+				regions[idx].EndRow, regions[idx].EndCol = row, col
+				delete(open, id)
+			}
+
+			i += n
+
+			continue
+		}
+
+		if view[i] == ansi.Marker {
+			n := sequenceLength(view[i:])
+			clean.WriteString(view[i : i+n])
+			i += n
+
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(view[i:])
+		clean.WriteString(view[i : i+size])
+		i += size
+
+		if r == '\n' {
+			rowWidths = append(rowWidths, col)
+			row++
+			col = 0
+
+			continue
+		}
+
+		col += runewidth.RuneWidth(r)
+	}
+
+	rowWidths = append(rowWidths, col)
+
+	for _, idx := range open {
+		regions[idx].EndRow = regions[idx].Row
+		regions[idx].EndCol = rowWidths[regions[idx].Row]
+	}
+
+	return clean.String(), regions
+}
+
+// Preserve appends the end markers that are contained in the original text
+// but were cut off in the truncated text such that the regions of truncated
+// parts end where the text was cut off.
+func Preserve(original string, truncated string) string {
+	if !strings.Contains(original, endMarker) {
+		return truncated
+	}
+
+	var missing strings.Builder
+
+	for rest := original; ; {
+		idx := strings.Index(rest, endMarker)
+		if idx < 0 {
+			break
+		}
+
+		_, n, ok := parseMarker(rest[idx:], endMarker)
+		if !ok {
+			break
+		}
+
+		marker := rest[idx : idx+n]
+		if !strings.Contains(truncated, marker) {
+			missing.WriteString(marker)
+		}
+
+		rest = rest[idx+n:]
+	}
+
+	return truncated + missing.String()
+}
+
+func parseMarker(s string, marker string) (id int, n int, ok bool) {
+	if !strings.HasPrefix(s, marker) {
+		return 0, 0, false
+	}
+
+	end := strings.IndexRune(s[len(marker):], terminator)
+	if end < 0 {
+		return 0, 0, false
+	}
+
+	id, err := strconv.Atoi(s[len(marker) : len(marker)+end])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return id, len(marker) + end + 1, true
+}
+
+// sequenceLength returns the length of the ANSI sequence at the start of s.
+func sequenceLength(s string) int {
+	for i := 1; i < len(s); i++ {
+		if ansi.IsTerminator(rune(s[i])) {
+			return i + 1
+		}
+	}
+
+	return len(s)
+}
@@ -0,0 +1,56 @@
+package region_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/internal/region"
+)
+
+func TestExtract(t *testing.T) {
+	t.Parallel()
+
+	view := "pick:\n  " + region.Mark(0, "\x1b[1m世界\x1b[0m") + " " + region.Mark(1, "b") + "\n"
+
+	clean, regions := region.Extract(view)
+	if clean != "pick:\n  \x1b[1m世界\x1b[0m b\n" {
+		t.Errorf("markers were not removed: %q", clean)
+	}
+
+	expected := []region.Region{
+		{ID: 0, Row: 1, Col: 2, EndRow: 1, EndCol: 6},
+		{ID: 1, Row: 1, Col: 7, EndRow: 1, EndCol: 8},
+	}
+	if !reflect.DeepEqual(regions, expected) {
+		t.Errorf("unexpected regions %+v, expected %+v", regions, expected)
+	}
+
+	if !regions[0].Contains(5, 1) || regions[0].Contains(6, 1) || regions[0].Contains(2, 0) {
+		t.Errorf("unexpected hit-test result for %+v", regions[0])
+	}
+}
+
+func TestExtractWrapped(t *testing.T) {
+	t.Parallel()
+
+	view := "> " + region.Mark(3, "aaaa bbbb") + "\nfooter"
+
+	_, regions := region.Extract(promptkit.WordWrap(view, 6))
+	expected := []region.Region{{ID: 3, Row: 0, Col: 2, EndRow: 1, EndCol: 4}}
+
+	if !reflect.DeepEqual(regions, expected) {
+		t.Errorf("unexpected regions %+v, expected %+v", regions, expected)
+	}
+
+	_, regions = region.Extract(promptkit.Truncate(view, 4))
+	expected = []region.Region{{ID: 3, Row: 0, Col: 2, EndRow: 0, EndCol: 4}}
+
+	if !reflect.DeepEqual(regions, expected) {
+		t.Errorf("unexpected regions after truncation %+v, expected %+v", regions, expected)
+	}
+
+	if regions[0].ContainsRow(1) {
+		t.Errorf("truncated region extends to the footer")
+	}
+}
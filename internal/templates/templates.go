@@ -0,0 +1,52 @@
+// Package templates describes the templates and function maps of the prompts
+// for error messages and debug output.
+package templates
+
+import (
+	"sort"
+	"text/template"
+)
+
+// Name returns the name of the built-in template that matches tmpl, "custom"
+// if tmpl is not a built-in template or "none" if it is empty.
+func Name(tmpl string, builtins map[string]string) string {
+	if tmpl == "" {
+		return "none"
+	}
+
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if builtins[name] == tmpl {
+			return name
+		}
+	}
+
+	return "custom"
+}
+
+// FuncNames returns the sorted and deduplicated names of the functions in all
+// function maps.
+func FuncNames(funcMaps ...template.FuncMap) []string {
+	unique := map[string]bool{}
+
+	for _, funcMap := range funcMaps {
+		for name := range funcMap {
+			unique[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(unique))
+	for name := range unique {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,40 @@
+package keypress
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/internal/templates"
+)
+
+// DebugConfig returns a human-readable description of the effective
+// configuration of the key press prompt. It is intended to be included in bug
+// reports and its format is not stable.
+func (k *KeyPress) DebugConfig() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Prompt: %q\n", k.Prompt)
+	fmt.Fprintf(&b, "Icon: %q\n", k.Icon)
+	fmt.Fprintf(&b, "ResultIcon: %q\n", k.ResultIcon)
+	fmt.Fprintf(&b, "AllowedRunes: %q\n", k.AllowedRunes)
+	fmt.Fprintf(&b, "Template: %s\n", templates.Name(k.Template,
+		map[string]string{"default": DefaultTemplate}))
+	fmt.Fprintf(&b, "ResultTemplate: %s\n", templates.Name(k.ResultTemplate,
+		map[string]string{"default": DefaultResultTemplate}))
+	fmt.Fprintf(&b, "ExtendedTemplateFuncs: %s\n", templates.FuncNames(k.ExtendedTemplateFuncs))
+	fmt.Fprintf(&b, "KeyMap: %+v\n", k.KeyMap)
+	fmt.Fprintf(&b, "WrapMode: %t\n", k.WrapMode != nil)
+	fmt.Fprintf(&b, "TrimBlankLines: %t\n", k.TrimBlankLines)
+	fmt.Fprintf(&b, "AltScreen: %t\n", k.AltScreen)
+	fmt.Fprintf(&b, "InitialWidth: %d\n", k.InitialWidth)
+	fmt.Fprintf(&b, "Managed: %t\n", k.Managed)
+	fmt.Fprintf(&b, "ForwardUnhandledKeys: %t\n", k.ForwardUnhandledKeys)
+	fmt.Fprintf(&b, "LineEnding: %q\n", k.LineEnding)
+	fmt.Fprintf(&b, "ProgramOptions: %d\n", len(k.ProgramOptions))
+	fmt.Fprintf(&b, "Output: %T\n", k.Output)
+	fmt.Fprintf(&b, "Input: %T\n", k.Input)
+	fmt.Fprintf(&b, "ColorProfile: %d\n", promptkit.ResolveColorProfile(k.ColorProfile))
+
+	return b.String()
+}
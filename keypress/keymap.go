@@ -0,0 +1,46 @@
+package keypress
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// NewDefaultKeyMap returns a KeyMap with sensible default key mappings that can
+// also be used as a starting point for customization.
+func NewDefaultKeyMap() *KeyMap {
+	return &KeyMap{
+		Abort:     []string{"ctrl+c"},
+		Interrupt: []string{},
+	}
+}
+
+// KeyMap defines the keys that trigger certain actions. The Abort keys abort
+// the prompt with promptkit.ErrAborted and the Interrupt keys abort it with
+// promptkit.ErrInterrupted such that the parent program can distinguish them.
+// By default, ctrl+c is an Abort key and no Interrupt keys are configured.
+type KeyMap struct {
+	Abort     []string
+	Interrupt []string
+}
+
+func keyMatches(key tea.KeyMsg, mapping []string) bool {
+	for _, m := range mapping {
+		if m == key.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateKeyMap returns true if the given key map contains at
+// least the bare minimum set of key bindings for the functional
+// prompt and false otherwise.
+func validateKeyMap(km *KeyMap) error {
+	if len(km.Abort) == 0 && len(km.Interrupt) == 0 {
+		return fmt.Errorf("no abort or interrupt key")
+	}
+
+	return nil
+}
@@ -0,0 +1,332 @@
+package keypress
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/internal/keys"
+	"github.com/muesli/termenv"
+)
+
+// DoneMsg is emitted by a Managed model when the prompt concludes or aborts
+// instead of quitting the program. Value and Err correspond to the result of
+// Model.Value. Afterwards, the model ignores all messages.
+type DoneMsg struct {
+	Value rune
+	Err   error
+}
+
+// Model implements the bubbletea.Model for a key press prompt.
+type Model struct {
+	*KeyPress
+
+	// Err holds errors that may occur during the execution of
+	// the key press prompt.
+	Err error
+
+	// MaxWidth limits the width of the view using the KeyPress's WrapMode.
+	MaxWidth int
+
+	tmpl       *template.Template
+	resultTmpl *template.Template
+
+	value       rune
+	pressed     bool
+	invalidRune rune
+
+	quitting bool
+
+	width int
+}
+
+// ensure that the Model interface is implemented.
+var _ tea.Model = &Model{}
+
+// NewModel returns a new model based on the provided key press prompt.
+func NewModel(keyPress *KeyPress) *Model {
+	return &Model{KeyPress: keyPress}
+}
+
+// Init initializes the key press prompt model.
+func (m *Model) Init() tea.Cmd {
+	m.width = zeroAwareMin(m.InitialWidth, m.MaxWidth)
+
+	m.tmpl, m.Err = m.initTemplate()
+	if m.Err != nil {
+		return m.quit()
+	}
+
+	m.resultTmpl, m.Err = m.initResultTemplate()
+	if m.Err != nil {
+		return m.quit()
+	}
+
+	return nil
+}
+
+func (m *Model) initTemplate() (*template.Template, error) {
+	return promptkit.ParseTemplate("view", m.Template,
+		termenv.TemplateFuncs(m.colorProfile()),
+		promptkit.UtilFuncMap(),
+		m.themeFuncs(),
+		m.ExtendedTemplateFuncs,
+	)
+}
+
+func (m *Model) initResultTemplate() (*template.Template, error) {
+	if m.ResultTemplate == "" {
+		return nil, nil
+	}
+
+	return promptkit.ParseTemplate("result", m.ResultTemplate,
+		termenv.TemplateFuncs(m.colorProfile()),
+		promptkit.UtilFuncMap(),
+		m.themeFuncs(),
+		m.ExtendedTemplateFuncs,
+	)
+}
+
+// themeFuncs returns the template functions of the Theme in which empty colors
+// fall back to the colors of the built-in templates.
+func (m *Model) themeFuncs() template.FuncMap {
+	return m.Theme.WithDefaults(defaultTheme).TemplateFuncs(m.colorProfile())
+}
+
+// colorProfile returns the configured ColorProfile unless another profile was
+// forced with promptkit.SetColorProfile.
+func (m *Model) colorProfile() termenv.Profile {
+	return promptkit.ResolveColorProfile(m.ColorProfile)
+}
+
+// Update updates the model based on the received message.
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.Managed && m.quitting {
+		// the parent program was already notified with a DoneMsg
+		return m, nil
+	}
+
+	if m.Err != nil {
+		return m, m.quit()
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		keys.Record(m.RecordKeys, msg)
+
+		if keyMatches(msg, m.KeyMap.Interrupt) {
+			m.Err = promptkit.ErrInterrupted
+			m.quitting = true
+
+			return m, m.quit()
+		}
+
+		if keyMatches(msg, m.KeyMap.Abort) {
+			m.Err = promptkit.ErrAborted
+			m.quitting = true
+
+			return m, m.quit()
+		}
+
+		r, ok := pressedRune(msg)
+		if !ok {
+			if m.ForwardUnhandledKeys {
+				return m, promptkit.ForwardKey(msg)
+			}
+
+			return m, nil
+		}
+
+		if !m.isAllowed(r) {
+			m.invalidRune = r
+
+			return m, nil
+		}
+
+		m.value = r
+		m.pressed = true
+		m.quitting = true
+
+		return m, m.quit()
+	case tea.WindowSizeMsg:
+		m.width = zeroAwareMin(msg.Width, m.MaxWidth)
+	case error:
+		m.Err = msg
+
+		return m, m.quit()
+	}
+
+	return m, nil
+}
+
+// pressedRune returns the rune that corresponds to the key press if it is a
+// single rune without modifiers.
+func pressedRune(msg tea.KeyMsg) (rune, bool) {
+	if msg.Alt {
+		return 0, false
+	}
+
+	switch msg.Type {
+	case tea.KeyRunes:
+		if len(msg.Runes) != 1 {
+			return 0, false
+		}
+
+		return msg.Runes[0], true
+	case tea.KeySpace:
+		return ' ', true
+	default:
+		return 0, false
+	}
+}
+
+func (m *Model) isAllowed(r rune) bool {
+	if len(m.AllowedRunes) == 0 {
+		return true
+	}
+
+	for _, allowed := range m.AllowedRunes {
+		if r == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// View renders the key press prompt.
+func (m *Model) View() string {
+	if m.quitting {
+		view, err := m.resultView()
+		if err != nil {
+			m.Err = err
+
+			return ""
+		}
+
+		return m.wrap(view)
+	}
+
+	// avoid panics if Quit is sent during Init
+	if m.tmpl == nil {
+		return ""
+	}
+
+	viewBuffer := &bytes.Buffer{}
+
+	invalidRune := ""
+	if m.invalidRune != 0 {
+		invalidRune = string(m.invalidRune)
+	}
+
+	err := m.tmpl.Execute(viewBuffer, map[string]interface{}{
+		"Prompt":        m.Prompt,
+		"Icon":          m.Icon,
+		"AllowedRunes":  m.allowedRuneStrings(),
+		"InvalidRune":   invalidRune,
+		"TerminalWidth": m.width,
+	})
+	if err != nil {
+		m.Err = err
+
+		return "Template Error: " + err.Error()
+	}
+
+	return m.wrap(viewBuffer.String())
+}
+
+func (m *Model) resultView() (string, error) {
+	viewBuffer := &bytes.Buffer{}
+
+	if m.ResultTemplate == "" {
+		return "", nil
+	}
+
+	if m.resultTmpl == nil {
+		return "", fmt.Errorf("rendering key press prompt without loaded template")
+	}
+
+	value, err := m.Value()
+	if err != nil {
+		return "", err
+	}
+
+	err = m.resultTmpl.Execute(viewBuffer, map[string]interface{}{
+		"FinalValue":       value,
+		"FinalValueString": string(value),
+		"Prompt":           m.Prompt,
+		"ResultIcon":       m.ResultIcon,
+		"AllowedRunes":     m.allowedRuneStrings(),
+		"TerminalWidth":    m.width,
+	})
+	if err != nil {
+		return "", fmt.Errorf("execute key press template: %w", err)
+	}
+
+	return viewBuffer.String(), nil
+}
+
+func (m *Model) allowedRuneStrings() []string {
+	allowed := make([]string, 0, len(m.AllowedRunes))
+
+	for _, r := range m.AllowedRunes {
+		allowed = append(allowed, string(r))
+	}
+
+	return allowed
+}
+
+// quit returns tea.Quit unless the prompt is managed by a parent program, in
+// which case it returns a command that emits a DoneMsg.
+func (m *Model) quit() tea.Cmd {
+	if m.Managed {
+		m.quitting = true
+		value, err := m.Value()
+
+		return func() tea.Msg {
+			return DoneMsg{Value: value, Err: err}
+		}
+	}
+
+	return tea.Quit
+}
+
+func (m *Model) wrap(text string) string {
+	if m.TrimBlankLines {
+		text = promptkit.TrimBlankLines(text)
+	}
+
+	if m.WrapMode == nil {
+		return text
+	}
+
+	return m.WrapMode(text, m.width)
+}
+
+// Value returns the pressed rune and error.
+func (m *Model) Value() (rune, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+
+	if !m.pressed {
+		return 0, fmt.Errorf("no key was pressed")
+	}
+
+	return m.value, nil
+}
+
+func zeroAwareMin(a int, b int) int {
+	switch {
+	case a == 0:
+		return b
+	case b == 0:
+		return a
+	case a > b:
+		return b
+	default:
+		return a
+	}
+}
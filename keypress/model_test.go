@@ -0,0 +1,317 @@
+package keypress_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/keypress"
+	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/reflow/ansi"
+	"github.com/muesli/termenv"
+)
+
+func TestAnyKey(t *testing.T) {
+	t.Parallel()
+
+	k := keypress.New("press any key")
+	k.ColorProfile = termenv.TrueColor
+	m := keypress.NewModel(k)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "any_key.golden")
+
+	cmd := test.Update(t, m, test.KeyMsg('x'))
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Errorf("key press did not produce quit signal")
+	}
+
+	if value := getValue(t, m); value != 'x' {
+		t.Errorf("unexpected value: %q, expected %q", value, 'x')
+	}
+
+	test.AssertGoldenView(t, m, "any_key_result.golden")
+}
+
+func TestAllowedRunes(t *testing.T) {
+	t.Parallel()
+
+	k := keypress.New("continue?", 'y', 'n')
+	k.ColorProfile = termenv.TrueColor
+	m := keypress.NewModel(k)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "allowed_runes.golden")
+
+	cmd := test.Update(t, m, test.KeyMsg('x'))
+	if cmd != nil {
+		t.Errorf("disallowed key press did not produce a no-op but %v", cmd)
+	}
+
+	v, err := m.Value()
+	if err == nil {
+		t.Errorf("getting value after disallowed key press did not error but %q", v)
+	}
+
+	view := test.StripANSI(m.View())
+	if !strings.Contains(view, "x is not allowed") {
+		t.Errorf("disallowed key was not indicated:\n%s", test.Indent(view))
+	}
+
+	test.AssertGoldenView(t, m, "allowed_runes_invalid.golden")
+
+	cmd = test.Update(t, m, test.KeyMsg('n'))
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Errorf("allowed key press did not produce quit signal")
+	}
+
+	if value := getValue(t, m); value != 'n' {
+		t.Errorf("unexpected value: %q, expected %q", value, 'n')
+	}
+}
+
+func TestTheme(t *testing.T) {
+	t.Parallel()
+
+	k := keypress.New("continue?", 'y', 'n')
+	k.Theme = promptkit.Theme{SelectedColor: "5", ErrorColor: "9"}
+	k.ColorProfile = termenv.TrueColor
+	m := keypress.NewModel(k)
+
+	test.Run(t, m, test.KeyMsg('x'))
+	assertNoError(t, m)
+
+	notAllowed := termenv.TrueColor.String("x is not allowed").Foreground(termenv.TrueColor.Color("9")).String()
+	if !strings.Contains(m.View(), notAllowed) {
+		t.Errorf("disallowed key was not rendered with the error color: %q", m.View())
+	}
+
+	test.Update(t, m, test.KeyMsg('n'))
+	assertNoError(t, m)
+
+	value := termenv.TrueColor.String("n").Foreground(termenv.TrueColor.Color("5")).String()
+	if !strings.Contains(m.View(), value) {
+		t.Errorf("result was not rendered with the selected color: %q", m.View())
+	}
+}
+
+func TestWrapWidths(t *testing.T) {
+	t.Parallel()
+
+	for _, width := range []int{20, 40, 80} {
+		width := width
+
+		t.Run(fmt.Sprint(width), func(t *testing.T) {
+			t.Parallel()
+
+			k := keypress.New("Do you really want to delete all files in the current directory?", 'y', 'n')
+			k.Icon = promptkit.DefaultIcons.Question
+			k.ColorProfile = termenv.TrueColor
+			m := keypress.NewModel(k)
+
+			test.Run(t, m, tea.WindowSizeMsg{Width: width, Height: 20})
+			assertNoError(t, m)
+			test.AssertGoldenView(t, m, fmt.Sprintf("wrap_%d.golden", width))
+
+			for i, line := range strings.Split(strings.TrimSuffix(m.View(), "\n"), "\n") {
+				if lineWidth := ansi.PrintableRuneWidth(line); lineWidth > width {
+					t.Errorf("line %q with width %d exceeds terminal width", line, lineWidth)
+				}
+
+				if i > 0 && !strings.HasPrefix(line, "  ") {
+					t.Errorf("continuation line %q is not indented under the prompt", line)
+				}
+			}
+		})
+	}
+}
+
+func TestIgnoreNonRuneKeys(t *testing.T) {
+	t.Parallel()
+
+	k := keypress.New("press any key")
+	k.ColorProfile = termenv.TrueColor
+	m := keypress.NewModel(k)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	cmd := test.Update(t, m, tea.KeyEnter)
+	if cmd != nil {
+		t.Errorf("non-rune key press did not produce a no-op but %v", cmd)
+	}
+
+	cmd = test.Update(t, m, tea.KeySpace)
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Errorf("space did not produce quit signal")
+	}
+
+	if value := getValue(t, m); value != ' ' {
+		t.Errorf("unexpected value: %q, expected %q", value, ' ')
+	}
+}
+
+func TestTrimBlankLines(t *testing.T) {
+	t.Parallel()
+
+	k := keypress.New("press any key")
+	k.Template = "\n\n{{ .Prompt }}\n\n"
+	k.ResultTemplate = "\n{{ .FinalValueString }}\n\n"
+	k.TrimBlankLines = true
+	m := keypress.NewModel(k)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	if view := m.View(); view != "press any key\n" {
+		t.Errorf("unexpected trimmed view: %q", view)
+	}
+
+	test.Update(t, m, test.KeyMsg('x'))
+
+	if view := m.View(); view != "x\n" {
+		t.Errorf("unexpected trimmed result view: %q", view)
+	}
+}
+
+func TestAbort(t *testing.T) {
+	t.Parallel()
+
+	k := keypress.New("press any key")
+	k.ColorProfile = termenv.TrueColor
+	m := keypress.NewModel(k)
+
+	test.Run(t, m, tea.KeyCtrlC)
+
+	if m.Err == nil {
+		t.Fatalf("aborting did not produce an error")
+	}
+
+	if !errors.Is(m.Err, promptkit.ErrAborted) {
+		t.Fatalf("aborting produced %q instead of %q", m.Err, promptkit.ErrAborted)
+	}
+
+	test.AssertGoldenView(t, m, "abort.golden")
+}
+
+func getValue(tb testing.TB, m *keypress.Model) rune {
+	tb.Helper()
+
+	v, err := m.Value()
+	if err != nil {
+		tb.Fatalf("value: %v", err)
+	}
+
+	return v
+}
+
+func assertNoError(tb testing.TB, m *keypress.Model) {
+	tb.Helper()
+
+	if m.Err != nil {
+		tb.Fatalf("model contains error: %v", m.Err)
+	}
+}
+
+func TestManaged(t *testing.T) {
+	t.Parallel()
+
+	k := keypress.New("press any key")
+	k.Managed = true
+	m := keypress.NewModel(k)
+
+	test.Run(t, m)
+
+	cmd := test.Update(t, m, test.KeyMsg('x'))
+	if cmd == nil {
+		t.Fatalf("managed model did not report its conclusion")
+	}
+
+	done, ok := cmd().(keypress.DoneMsg)
+	if !ok || done.Value != 'x' || done.Err != nil {
+		t.Errorf("unexpected conclusion %#v", done)
+	}
+
+	if cmd := test.Update(t, m, test.KeyMsg('y')); cmd != nil {
+		t.Errorf("concluded model still updates")
+	}
+
+	if value := getValue(t, m); value != 'x' {
+		t.Errorf("value changed after the conclusion to %q", value)
+	}
+}
+
+func TestForwardUnhandledKeys(t *testing.T) {
+	t.Parallel()
+
+	k := keypress.New("press a", 'a')
+	m := keypress.NewModel(k)
+
+	test.Run(t, m)
+
+	cmd := test.Update(t, m, tea.KeyF1)
+	if cmd != nil {
+		t.Fatalf("unhandled key was forwarded without ForwardUnhandledKeys")
+	}
+
+	k.ForwardUnhandledKeys = true
+
+	cmd = test.Update(t, m, tea.KeyF1)
+	if cmd == nil {
+		t.Fatalf("unhandled key was not forwarded")
+	}
+
+	expected := promptkit.UnhandledKeyMsg{Key: tea.KeyMsg{Type: tea.KeyF1}}
+	if msg := cmd(); !reflect.DeepEqual(msg, expected) {
+		t.Errorf("unexpected message %#v, expected %#v", msg, expected)
+	}
+
+	cmd = test.Update(t, m, test.KeyMsg('b'))
+	if cmd != nil {
+		if _, ok := cmd().(promptkit.UnhandledKeyMsg); ok {
+			t.Errorf("handled key was forwarded")
+		}
+	}
+}
+
+func TestProgramOptions(t *testing.T) {
+	t.Parallel()
+
+	output := &bytes.Buffer{}
+	override := &bytes.Buffer{}
+	applied := false
+
+	k := keypress.New("key?")
+	k.ColorProfile = termenv.Ascii
+	k.Input = strings.NewReader("a")
+	k.Output = output
+	k.ProgramOptions = []tea.ProgramOption{
+		tea.WithOutput(override),
+		func(*tea.Program) { applied = true },
+	}
+
+	_, err := k.RunPrompt()
+	if err != nil {
+		t.Fatalf("run prompt: %v", err)
+	}
+
+	if !applied {
+		t.Errorf("program option was not applied")
+	}
+
+	if output.Len() != 0 {
+		t.Errorf("program option did not override the output: %q", output.String())
+	}
+
+	if !strings.Contains(override.String(), "key?") {
+		t.Errorf("key press prompt was not rendered to the overridden output: %q",
+			override.String())
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Package keypress implements a prompt that waits for a single key press and
+returns the corresponding rune without requiring the user to press enter. It
+also offers an optional list of allowed runes as well as customizable appreance
+and a customizable key map.
+*/
+package keypress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit"
+	"github.com/muesli/termenv"
+)
+
+const (
+	// DefaultTemplate defines the default appearance of the key press prompt
+	// and can be copied as a starting point for a custom template.
+	DefaultTemplate = `
+{{- if .Icon }}{{ print .Icon " " }}{{ end -}}
+{{- Bold .Prompt -}}
+{{- if .AllowedRunes }} [
+  {{- range $i, $r := .AllowedRunes }}
+    {{- if $i }}/{{ end }}{{ $r }}
+  {{- end -}}
+]{{ end -}}
+{{- if .InvalidRune }} {{ ThemeError (printf (Strings).NotAllowed .InvalidRune) }}
+{{- end -}}
+`
+
+	// DefaultResultTemplate defines the default appearance with which the
+	// finale result of the prompt is presented.
+	DefaultResultTemplate = `
+{{- if .ResultIcon }}{{ print .ResultIcon " " }}{{ end -}}
+{{- print .Prompt " " (ThemeSelected .FinalValueString) "\n" -}}
+`
+)
+
+// defaultTheme holds the colors of the built-in templates which are used for
+// the empty colors of the Theme.
+var defaultTheme = promptkit.Theme{SelectedColor: "32", ErrorColor: "1"}
+
+// KeyPress represents a configurable key press prompt.
+type KeyPress struct {
+	// Prompt holds the question or instruction.
+	Prompt string
+
+	// Icon is rendered in front of the prompt by the built-in templates, for
+	// example promptkit.DefaultIcons.Question. ResultIcon is rendered in front
+	// of the prompt by the built-in result templates, for example
+	// promptkit.DefaultIcons.Success. By default, no icons are rendered.
+	Icon       string
+	ResultIcon string
+
+	// AllowedRunes restricts the runes that are accepted. If a rune that is
+	// not in AllowedRunes is pressed, the prompt indicates that the key is not
+	// allowed and waits for the next key press. If AllowedRunes is empty, any
+	// rune is accepted.
+	AllowedRunes []rune
+
+	// Template holds the display template. A custom template can be used to
+	// completely customize the appearance of the key press prompt. If empty,
+	// the DefaultTemplate is used. The following variables and functions are
+	// available:
+	//
+	//  * Prompt string: The configured prompt.
+	//  * Icon string: The configured Icon.
+	//  * AllowedRunes []string: The configured allowed runes as strings.
+	//  * InvalidRune string: The last rune that was pressed but is not
+	//    allowed or an empty string if no such rune was pressed.
+	//  * TerminalWidth int: The width of the terminal.
+	//  * promptkit.UtilFuncMap: Handy helper functions.
+	//  * termenv TemplateFuncs (see https://github.com/muesli/termenv).
+	//  * The functions of the configured Theme, see
+	//    promptkit.Theme.TemplateFuncs.
+	//  * The functions specified in ExtendedTemplateFuncs.
+	Template string
+
+	// ResultTemplate is rendered as soon as a key has been pressed. It is
+	// intended to permanently indicate the result of the prompt when the input
+	// itself has disappeared. This template is only rendered in the Run()
+	// method and NOT when the key press prompt is used as a model. The
+	// following variables and functions are available:
+	//
+	//  * FinalValue rune: The rune that was pressed.
+	//  * FinalValueString string: The rune that was pressed as a string.
+	//  * Prompt string: The configured prompt.
+	//  * ResultIcon string: The configured ResultIcon.
+	//  * AllowedRunes []string: The configured allowed runes as strings.
+	//  * TerminalWidth int: The width of the terminal.
+	//  * promptkit.UtilFuncMap: Handy helper functions.
+	//  * termenv TemplateFuncs (see https://github.com/muesli/termenv).
+	//  * The functions of the configured Theme, see
+	//    promptkit.Theme.TemplateFuncs.
+	//  * The functions specified in ExtendedTemplateFuncs.
+	ResultTemplate string
+
+	// ExtendedTemplateFuncs can be used to add additional functions to the
+	// evaluation scope of the templates.
+	ExtendedTemplateFuncs template.FuncMap
+
+	// Theme colors the final value and the message about keys that are not
+	// allowed in the built-in templates. Its empty colors fall back to the
+	// colors of the built-in templates.
+	Theme promptkit.Theme
+
+	// KeyMap determines with which keys the key press prompt is controlled.
+	// By default, DefaultKeyMap is used.
+	KeyMap *KeyMap
+
+	// WrapMode decides which way the prompt view is wrapped if it does not fit
+	// the terminal. It can be a WrapMode provided by promptkit or a custom
+	// function. By default it is promptkit.PrefixHangingIndent such that wrapped
+	// lines stay aligned under the icon. It can also be nil which disables
+	// wrapping and likely causes output glitches.
+	WrapMode promptkit.WrapMode
+
+	// TrimBlankLines removes leading and trailing blank lines from the rendered
+	// view such that templates with surrounding whitespace do not produce stray
+	// empty lines. By default, the view is rendered unmodified.
+	TrimBlankLines bool
+
+	// AltScreen renders the prompt in the alternate screen buffer such that it
+	// does not scroll the terminal's scrollback. The result is printed to the
+	// normal screen after the prompt has concluded.
+	AltScreen bool
+
+	// RecordKeys collects all key presses that are processed by the key press
+	// prompt if it is not nil. The recorded keys can be replayed with
+	// promptkit.ReplayKeys, for example to reproduce a reported bug.
+	RecordKeys *[]tea.KeyMsg
+
+	// InitialWidth is the terminal width that is assumed until the terminal
+	// reports its actual size. Together with Input, Output and ColorProfile,
+	// it allows serving the prompt over connections where the terminal cannot be
+	// detected, such as SSH sessions. If it is 0, the width is unknown until
+	// the first tea.WindowSizeMsg.
+	InitialWidth int
+
+	// Managed disables everything the prompt does to the terminal or the program
+	// beyond rendering its view such that it can be embedded in a parent bubbletea
+	// program that owns the terminal. When set, the model does not return tea.Quit
+	// when the prompt concludes or aborts but a command that emits a DoneMsg with
+	// the result such that the parent program can decide what to do next. The
+	// model stops updating afterwards and RunPrompt should not be used.
+	Managed bool
+
+	// ForwardUnhandledKeys makes the model emit a promptkit.UnhandledKeyMsg for
+	// key presses that match none of the bindings of the KeyMap and do not
+	// correspond to a single character such that a parent program that embeds
+	// the prompt can handle them. By default, such keys are ignored, which is
+	// what RunPrompt expects.
+	ForwardUnhandledKeys bool
+
+	// LineEnding is used for the line breaks of the output that is written
+	// outside of the bubbletea renderer, which is the result that is printed
+	// after the AltScreen was left. Raw terminals that do not translate line
+	// feeds print such output like a staircase, which promptkit.CRLF avoids. By
+	// default, "\n" is used. The views rendered while the prompt is running
+	// always end their lines with "\r\n".
+	LineEnding string
+
+	// ProgramOptions are passed to tea.NewProgram when RunPrompt starts the
+	// interactive prompt, for example tea.WithoutSignalHandler. They are applied
+	// after the built-in options for Input, Output and AltScreen such that they
+	// can override them.
+	ProgramOptions []tea.ProgramOption
+
+	// Output is the output writer, by default os.Stdout is used.
+	Output io.Writer
+	// Input is the input reader, by default, os.Stdin is used.
+	Input io.Reader
+
+	// ColorProfile determines how colors are rendered. By default, the terminal
+	// is queried. A profile that is forced with promptkit.SetColorProfile takes
+	// precedence and a single prompt cannot opt out of it.
+	ColorProfile termenv.Profile
+}
+
+// New creates a new key press prompt. If allowedRunes are specified, only these
+// runes are accepted. See the KeyPress properties for more documentation.
+func New(prompt string, allowedRunes ...rune) *KeyPress {
+	return &KeyPress{
+		Prompt:                prompt,
+		AllowedRunes:          allowedRunes,
+		Template:              DefaultTemplate,
+		ResultTemplate:        DefaultResultTemplate,
+		KeyMap:                NewDefaultKeyMap(),
+		ExtendedTemplateFuncs: template.FuncMap{},
+		WrapMode:              promptkit.PrefixHangingIndent,
+		Output:                os.Stdout,
+		Input:                 os.Stdin,
+	}
+}
+
+// ValidateConfig checks the key map and the templates of the key press
+// prompt without running it.
+func (k *KeyPress) ValidateConfig() error {
+	err := validateKeyMap(k.KeyMap)
+	if err != nil {
+		return fmt.Errorf("insufficient key map: %w", err)
+	}
+
+	m := NewModel(k)
+
+	_, err = m.initTemplate()
+	if err != nil {
+		return err
+	}
+
+	_, err = m.initResultTemplate()
+
+	return err
+}
+
+var _ promptkit.ConfigValidator = &KeyPress{}
+
+// RunPrompt executes the key press prompt.
+func (k *KeyPress) RunPrompt() (rune, error) {
+	err := validateKeyMap(k.KeyMap)
+	if err != nil {
+		return 0, fmt.Errorf("insufficient key map: %w", err)
+	}
+
+	m := NewModel(k)
+
+	err = promptkit.Run(m, promptkit.WithOutput(k.Output), promptkit.WithInput(k.Input),
+		promptkit.WithAltScreen(k.AltScreen),
+		promptkit.WithLineEnding(k.LineEnding), promptkit.WithProgramOptions(k.ProgramOptions...))
+	if err != nil {
+		return 0, err
+	}
+
+	return m.Value()
+}
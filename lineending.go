@@ -0,0 +1,29 @@
+package promptkit
+
+import "strings"
+
+// CRLF is the line ending for raw terminals that do not translate a line feed
+// into a carriage return and a line feed, see ConvertLineEndings.
+const CRLF = "\r\n"
+
+// ConvertLineEndings replaces all line feeds in the text with the given line
+// ending. Line feeds that are already preceded by a carriage return are left
+// as they are such that the conversion can be applied more than once. If the
+// line ending is empty or "\n", the text is returned unmodified.
+func ConvertLineEndings(text string, lineEnding string) string {
+	if lineEnding == "" || lineEnding == "\n" {
+		return text
+	}
+
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", lineEnding)
+}
+
+// WithLineEnding converts the line endings of output that Run writes itself,
+// such as the final view after the alternate screen was left, see
+// ConvertLineEndings. The views rendered by the bubbletea program already end
+// their lines with "\r\n".
+func WithLineEnding(lineEnding string) RunOption {
+	return func(c *runConfig) {
+		c.lineEnding = lineEnding
+	}
+}
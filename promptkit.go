@@ -12,7 +12,9 @@ import (
 	"fmt"
 	"strings"
 	"text/template"
+	"unicode"
 
+	"github.com/erikgeiser/promptkit/internal/region"
 	"github.com/muesli/reflow/ansi"
 	"github.com/muesli/reflow/truncate"
 	"github.com/muesli/reflow/wordwrap"
@@ -22,10 +24,23 @@ import (
 // ErrAborted is returned when the prompt was aborted.
 var ErrAborted = fmt.Errorf("prompt aborted")
 
+// ErrInterrupted is returned when the prompt was interrupted with one of the
+// Interrupt keys of its key map. In contrast to ErrAborted, it signals that the
+// key press was intended for the parent program, for example to exit the whole
+// program instead of only the prompt.
+var ErrInterrupted = fmt.Errorf("prompt interrupted")
+
 // UtilFuncMap returns a template.FuncMap with handy utility functions for
 // prompt templates.
 //
 //   - Repeat(string, int) string: Identical to strings.Repeat.
+//   - Truncate(string, int) string: TruncateString, cuts the string such that
+//     it is at most as wide as the given width on the screen.
+//   - Pad(string, int) string: Pad, appends spaces such that the string is at
+//     least as wide as the given width on the screen, e.g. for aligning
+//     columns.
+//   - Upper(string) string: Identical to strings.ToUpper.
+//   - Lower(string) string: Identical to strings.ToLower.
 //   - Len(string): reflow/ansi.PrintableRuneWidth, Len works like len but is
 //     aware of ansi codes and returns the length of the string as it appears
 //     on the screen.
@@ -34,10 +49,23 @@ var ErrAborted = fmt.Errorf("prompt aborted")
 //   - Add(int, int) int: The sum of two ints.
 //   - Sub(int, int) int: The difference of two ints.
 //   - Mul(int, int) int: The product of two ints.
+//   - IndexToLetter(int) string: Formats an index as letters such that 0 is
+//     a, 25 is z, 26 is aa and so on.
+//   - Fill(string, int) string: Repeats a pattern such that it is exactly as
+//     wide as the given width on the screen, e.g. for separators that span
+//     the TerminalWidth.
+//   - Strings() Strings: The built-in texts as returned by CurrentStrings,
+//     e.g. (Strings).Yes.
 func UtilFuncMap() template.FuncMap {
 	return template.FuncMap{
 		"Repeat": strings.Repeat,
+		"Upper":  strings.ToUpper,
+		"Lower":  strings.ToLower,
 		"Len":    ansi.PrintableRuneWidth,
+
+		"Truncate": TruncateString,
+		"Pad":      Pad,
+
 		"Min": func(a, b int) int {
 			if a <= b {
 				return a
@@ -55,9 +83,101 @@ func UtilFuncMap() template.FuncMap {
 		"Add": func(a, b int) int { return a + b },
 		"Sub": func(a, b int) int { return a - b },
 		"Mul": func(a, b int) int { return a * b },
+
+		"IndexToLetter": IndexToLetter,
+		"Fill":          Fill,
+		"Strings":       CurrentStrings,
 	}
 }
 
+// Fill repeats the pattern such that the result occupies exactly width cells
+// on the screen. In contrast to strings.Repeat, it takes the display width of
+// each rune into account, so that box-drawing characters and wide runes do not
+// cause the result to overflow. The last repetition is cut off if necessary
+// and a wide rune that does not fit anymore is replaced by a space. The pattern
+// should not contain ANSI sequences.
+func Fill(pattern string, width int) string {
+	var filled strings.Builder
+
+	filledWidth := 0
+
+	for filledWidth < width {
+		progress := false
+
+		for _, r := range pattern {
+			runeWidth := ansi.PrintableRuneWidth(string(r))
+			if runeWidth == 0 {
+				continue
+			}
+
+			if filledWidth+runeWidth > width {
+				filled.WriteString(strings.Repeat(" ", width-filledWidth))
+
+				return filled.String()
+			}
+
+			filled.WriteRune(r)
+			filledWidth += runeWidth
+			progress = true
+
+			if filledWidth == width {
+				return filled.String()
+			}
+		}
+
+		if !progress {
+			break
+		}
+	}
+
+	return filled.String()
+}
+
+// TruncateString cuts the input such that it occupies at most width cells on
+// the screen. ANSI sequences are preserved and do not count towards the width
+// and wide runes that do not fit anymore are dropped. In contrast to the
+// Truncate WrapMode, it does not append a trailing newline and a width of zero
+// or less results in an empty string.
+func TruncateString(input string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	return region.Preserve(input, truncate.String(input, uint(width)))
+}
+
+// Pad appends spaces to the input such that it occupies at least width cells on
+// the screen. The width is measured like Len such that ANSI sequences and wide
+// runes are taken into account. Inputs that are already wider are returned
+// unchanged, so Pad can be combined with TruncateString to produce columns of a
+// fixed width.
+func Pad(input string, width int) string {
+	padding := width - ansi.PrintableRuneWidth(input)
+	if padding <= 0 {
+		return input
+	}
+
+	return input + strings.Repeat(" ", padding)
+}
+
+// IndexToLetter formats a zero-based index as letters such that 0 is a, 25 is
+// z, 26 is aa, 27 is ab and so on. Negative indices produce an empty string.
+func IndexToLetter(idx int) string {
+	if idx < 0 {
+		return ""
+	}
+
+	const nLetters = 26
+
+	var letters []byte
+
+	for idx++; idx > 0; idx = (idx - 1) / nLetters {
+		letters = append([]byte{byte('a' + (idx-1)%nLetters)}, letters...)
+	}
+
+	return string(letters)
+}
+
 // WrapMode decides in which way text is wrapped.
 type WrapMode func(string, int) string
 
@@ -96,10 +216,210 @@ func Truncate(input string, width int) string {
 	scanner := bufio.NewScanner(strings.NewReader(input))
 
 	for scanner.Scan() {
-		truncated.WriteString(truncate.String(scanner.Text(), uint(width)) + "\n")
+		line := scanner.Text()
+		truncated.WriteString(region.Preserve(line, truncate.String(line, uint(width))) + "\n")
 	}
 
 	return truncated.String()
 }
 
 var _ WrapMode = Truncate
+
+// HangingIndent returns a WrapMode that performs a word wrap like WordWrap but
+// indents all continuation lines of a wrapped line by the given number of
+// cells, for example such that a long prompt that follows a "? " icon stays
+// aligned under its first line. Widths are measured on the visible runes such
+// that ANSI sequences in the input are taken into account. The built-in
+// prompts use PrefixHangingIndent by default.
+func HangingIndent(indent int) WrapMode {
+	return func(input string, width int) string {
+		if width == 0 || indent >= width {
+			return WordWrap(input, width)
+		}
+
+		lines := strings.Split(input, "\n")
+		for i, line := range lines {
+			lines[i] = hangingIndentLine(line, width, indent)
+		}
+
+		return strings.Join(lines, "\n")
+	}
+}
+
+// PrefixHangingIndent is a WrapMode like HangingIndent that derives the indent
+// of each line from its prefix instead of using a fixed indent. The prefix is
+// the leading whitespace of the line followed by an icon without letters or
+// digits and the spaces after it, for example "? " in front of a prompt or
+// "  ▸ " in front of a selected choice, such that a line without an icon is
+// aligned under its leading whitespace.
+func PrefixHangingIndent(input string, width int) string {
+	if width == 0 {
+		return input
+	}
+
+	lines := strings.Split(input, "\n")
+	for i, line := range lines {
+		indent := hangingPrefixWidth(line)
+		if indent >= width {
+			lines[i] = WordWrap(line, width)
+
+			continue
+		}
+
+		lines[i] = hangingIndentLine(line, width, indent)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+var _ WrapMode = PrefixHangingIndent
+
+// hangingPrefixWidth returns the width of the prefix of the line as described
+// in PrefixHangingIndent.
+func hangingPrefixWidth(line string) int {
+	var (
+		visible    []rune
+		inSequence bool
+	)
+
+	for _, r := range line {
+		switch {
+		case r == ansi.Marker:
+			inSequence = true
+		case inSequence:
+			inSequence = !ansi.IsTerminator(r)
+		default:
+			visible = append(visible, r)
+		}
+	}
+
+	i := 0
+	for i < len(visible) && visible[i] == ' ' {
+		i++
+	}
+
+	iconStart := i
+	for i < len(visible) && visible[i] != ' ' && !unicode.IsLetter(visible[i]) &&
+		!unicode.IsDigit(visible[i]) {
+		i++
+	}
+
+	if i == iconStart || i == len(visible) || visible[i] != ' ' {
+		return ansi.PrintableRuneWidth(string(visible[:iconStart]))
+	}
+
+	for i < len(visible) && visible[i] == ' ' {
+		i++
+	}
+
+	return ansi.PrintableRuneWidth(string(visible[:i]))
+}
+
+// hangingIndentLine wraps a single line greedily at word boundaries such that
+// the first line is at most width wide and the indented continuation lines
+// are at most width - indent wide. Words that do not fit on a line of their
+// own are wrapped hard.
+func hangingIndentLine(line string, width int, indent int) string {
+	if ansi.PrintableRuneWidth(line) <= width {
+		return line
+	}
+
+	var (
+		wrapped      strings.Builder
+		lineWidth    int
+		limit        = width
+		startOfLine  = true
+		continuation = "\n" + strings.Repeat(" ", indent)
+	)
+
+	for _, word := range strings.Split(line, " ") {
+		wordWidth := ansi.PrintableRuneWidth(word)
+
+		// words that do not fit on a line of their own are wrapped hard right
+		// away instead of leaving the rest of the current line empty
+		fitsOnNewLine := wordWidth <= width-indent
+
+		if !startOfLine && lineWidth+1+wordWidth > limit && (fitsOnNewLine || lineWidth+1 >= limit) {
+			// spaces at the wrap point are dropped such that padded lines do
+			// not wrap into blank lines, but ANSI sequences are kept
+			if wordWidth == 0 {
+				wrapped.WriteString(word)
+
+				continue
+			}
+
+			wrapped.WriteString(continuation)
+
+			lineWidth, limit, startOfLine = 0, width-indent, true
+		}
+
+		if !startOfLine {
+			wrapped.WriteString(" ")
+			lineWidth++
+		}
+
+		if wordWidth > limit-lineWidth && strings.Contains(wrap.String(word, limit-lineWidth), "\n") {
+			// the first piece fills the rest of the current line and the
+			// remainder is wrapped at the width of the continuation lines
+			first := strings.SplitN(wrap.String(word, limit-lineWidth), "\n", 2)
+			pieces := strings.Split(wrap.String(strings.ReplaceAll(first[1], "\n", ""), width-indent), "\n")
+			word = first[0] + continuation + strings.Join(pieces, continuation)
+			wordWidth = ansi.PrintableRuneWidth(pieces[len(pieces)-1])
+			limit = width - indent
+		}
+
+		wrapped.WriteString(word)
+		lineWidth += wordWidth
+		startOfLine = false
+	}
+
+	return wrapped.String()
+}
+
+// MeasureHeight returns the number of terminal rows that the view occupies when
+// it is displayed in a terminal with the given width, taking into account that
+// lines which are longer than the width are wrapped by the terminal. If width
+// is 0, no wrapping is assumed.
+func MeasureHeight(view string, width int) int {
+	height := 0
+
+	for _, line := range strings.Split(view, "\n") {
+		lineWidth := ansi.PrintableRuneWidth(line)
+		if width <= 0 || lineWidth <= width {
+			height++
+
+			continue
+		}
+
+		height += (lineWidth + width - 1) / width
+	}
+
+	return height
+}
+
+// TrimBlankLines removes leading and trailing lines that are empty or only
+// consist of whitespace and ANSI sequences. A trailing newline is preserved if
+// the input ends with one.
+func TrimBlankLines(input string) string {
+	lines := strings.Split(input, "\n")
+	isBlank := func(line string) bool {
+		return ansi.PrintableRuneWidth(strings.TrimSpace(line)) == 0
+	}
+
+	start := 0
+	for start < len(lines) && isBlank(lines[start]) {
+		start++
+	}
+
+	end := len(lines)
+	for end > start && isBlank(lines[end-1]) {
+		end--
+	}
+
+	trimmed := strings.Join(lines[start:end], "\n")
+	if trimmed != "" && strings.HasSuffix(input, "\n") {
+		trimmed += "\n"
+	}
+
+	return trimmed
+}
@@ -1,10 +1,23 @@
 package promptkit_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
 	"testing"
+	"text/template"
+	"time"
 
 	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/confirmation"
 	"github.com/erikgeiser/promptkit/test"
+	"github.com/erikgeiser/promptkit/textinput"
+	"github.com/muesli/reflow/ansi"
+	"github.com/muesli/termenv"
 )
 
 func TestWordWrap(t *testing.T) {
@@ -31,6 +44,200 @@ func TestTruncate(t *testing.T) {
 	assertEqual(t, expected, promptkit.Truncate(text, 6))
 }
 
+func TestHangingIndent(t *testing.T) {
+	t.Parallel()
+
+	text := "? \x1b[1mDo you really want to delete all files in the current directory?\x1b[0m [y/N]\n" +
+		"second line that is also rather long and has to be wrapped"
+
+	for _, width := range []int{20, 40, 80} {
+		width := width
+
+		t.Run(fmt.Sprintf("width %d", width), func(t *testing.T) {
+			t.Parallel()
+
+			wrapped := promptkit.HangingIndent(2)(text, width)
+
+			var words []string
+
+			for i, line := range strings.Split(wrapped, "\n") {
+				if lineWidth := ansi.PrintableRuneWidth(line); lineWidth > width {
+					t.Errorf("line %d %q with width %d exceeds %d", i, line, lineWidth, width)
+				}
+
+				if strings.HasPrefix(line, "   ") {
+					t.Errorf("line %d %q is indented too far", i, line)
+				}
+
+				words = append(words, strings.Fields(test.StripANSI(line))...)
+			}
+
+			if strings.Join(words, " ") != strings.Join(strings.Fields(test.StripANSI(text)), " ") {
+				t.Errorf("wrapping changed the text:\n%s", test.Indent(wrapped))
+			}
+
+			for i, line := range strings.Split(wrapped, "\n") {
+				isStartOfLine := strings.HasPrefix(line, "? ") || strings.HasPrefix(line, "second")
+				if !isStartOfLine && !strings.HasPrefix(line, "  ") {
+					t.Errorf("continuation line %d %q is not indented", i, line)
+				}
+			}
+		})
+	}
+
+	expected := "? \x1b[1mDo you really want\n  to delete all\n  files in the\n  current directory?\x1b[0m\n  [y/N]"
+	assertEqual(t, expected, promptkit.HangingIndent(2)(strings.Split(text, "\n")[0], 20))
+}
+
+func TestPrefixHangingIndent(t *testing.T) {
+	t.Parallel()
+
+	text := "? \x1b[1mDo you really want to delete all files?\x1b[0m\n" +
+		"  ▸ Yes, delete them all\n" +
+		"plain text without an icon"
+
+	expected := "? \x1b[1mDo you really want\n  to delete all\n  files?\x1b[0m\n" +
+		"  ▸ Yes, delete them\n    all\n" +
+		"plain text without\nan icon"
+	assertEqual(t, expected, promptkit.PrefixHangingIndent(text, 20))
+	assertEqual(t, text, promptkit.PrefixHangingIndent(text, 0))
+
+	// long words continue on the current line and padding does not wrap
+	assertEqual(t, "  ▸ a-very-long-file\n    -name.txt", promptkit.PrefixHangingIndent("  ▸ a-very-long-file-name.txt", 20))
+	assertEqual(t, "Filter: abc"+strings.Repeat(" ", 9), promptkit.PrefixHangingIndent("Filter: abc"+strings.Repeat(" ", 30), 20))
+}
+
+func TestFill(t *testing.T) {
+	t.Parallel()
+
+	assertEqual(t, "─────", promptkit.Fill("─", 5))
+	assertEqual(t, "-=-=-", promptkit.Fill("-=", 5))
+	assertEqual(t, "世界世 ", promptkit.Fill("世界", 7))
+	assertEqual(t, "", promptkit.Fill("", 5))
+	assertEqual(t, "", promptkit.Fill("─", 0))
+}
+
+func TestTruncateString(t *testing.T) {
+	t.Parallel()
+
+	assertEqual(t, "hel", promptkit.TruncateString("hello", 3))
+	assertEqual(t, "世", promptkit.TruncateString("世界", 3))
+	assertEqual(t, "\x1b[1mhe\x1b[0m", promptkit.TruncateString("\x1b[1mhello\x1b[0m", 2))
+	assertEqual(t, "hi", promptkit.TruncateString("hi", 5))
+	assertEqual(t, "", promptkit.TruncateString("hello", 0))
+}
+
+func TestPad(t *testing.T) {
+	t.Parallel()
+
+	assertEqual(t, "ab   ", promptkit.Pad("ab", 5))
+	assertEqual(t, "世界 ", promptkit.Pad("世界", 5))
+	assertEqual(t, "\x1b[1mab\x1b[0m   ", promptkit.Pad("\x1b[1mab\x1b[0m", 5))
+	assertEqual(t, "hello", promptkit.Pad("hello", 3))
+}
+
+func TestUtilFuncMapColumns(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := promptkit.ParseTemplate("columns",
+		`{{ range . }}{{ Pad (Truncate . 4) 4 }}|{{ Repeat "-" 2 }}{{ "\n" }}{{ end }}`,
+		promptkit.UtilFuncMap())
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+
+	var buf bytes.Buffer
+
+	err = tmpl.Execute(&buf, []string{"a", "世界世界", "abcdef"})
+	if err != nil {
+		t.Fatalf("execute template: %v", err)
+	}
+
+	assertEqual(t, "a   |--\n世界|--\nabcd|--\n", buf.String())
+}
+
+func TestConvertLineEndings(t *testing.T) {
+	t.Parallel()
+
+	assertEqual(t, "a\r\nb\r\n", promptkit.ConvertLineEndings("a\nb\n", promptkit.CRLF))
+	assertEqual(t, "a\r\nb\r\n", promptkit.ConvertLineEndings("a\r\nb\n", promptkit.CRLF))
+	assertEqual(t, "a\nb", promptkit.ConvertLineEndings("a\nb", ""))
+	assertEqual(t, "a\nb", promptkit.ConvertLineEndings("a\nb", "\n"))
+}
+
+func TestIndexToLetter(t *testing.T) {
+	t.Parallel()
+
+	for idx, expected := range map[int]string{
+		-1: "", 0: "a", 1: "b", 25: "z", 26: "aa", 27: "ab", 51: "az", 52: "ba", 701: "zz", 702: "aaa",
+	} {
+		assertEqual(t, expected, promptkit.IndexToLetter(idx))
+	}
+}
+
+func TestMeasureHeight(t *testing.T) {
+	t.Parallel()
+
+	view := "0123456789\n\x1b[1m0123\x1b[0m\n\n012345678901"
+
+	for width, expected := range map[int]int{0: 4, 4: 8, 6: 6, 12: 4} {
+		if height := promptkit.MeasureHeight(view, width); height != expected {
+			t.Errorf("unexpected height at width %d: %d, expected %d", width, height, expected)
+		}
+	}
+}
+
+func TestTrimBlankLines(t *testing.T) {
+	t.Parallel()
+
+	text := "\n  \n\x1b[1m\x1b[0m\nfoo\n\nbar\n \n\n"
+	expected := "foo\n\nbar\n"
+	assertEqual(t, expected, promptkit.TrimBlankLines(text))
+	assertEqual(t, "foo", promptkit.TrimBlankLines("\nfoo"))
+	assertEqual(t, "", promptkit.TrimBlankLines("\n\n"))
+}
+
+func TestSetRand(t *testing.T) { //nolint:paralleltest
+	draw := func() []int {
+		promptkit.SetRand(rand.New(rand.NewSource(42))) //nolint:gosec
+
+		values := make([]int, 0, 10)
+		for i := 0; i < 10; i++ {
+			values = append(values, promptkit.Intn(100))
+		}
+
+		return values
+	}
+
+	first, second := draw(), draw()
+
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("seeded generators produced different values: %v and %v", first, second)
+		}
+	}
+
+	promptkit.SetRand(nil)
+}
+
+func TestParseTemplateUndefinedFunc(t *testing.T) {
+	t.Parallel()
+
+	_, err := promptkit.ParseTemplate("view", `{{ Foo }}{{ Bar }}`,
+		template.FuncMap{"Bar": func() string { return "" }},
+		template.FuncMap{"Baz": func() string { return "" }},
+	)
+	if err == nil {
+		t.Fatalf("parsing template with undefined function did not fail")
+	}
+
+	for _, expected := range []string{`"Foo"`, "Bar, Baz"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("error %q does not contain %q", err, expected)
+		}
+	}
+}
+
 func assertEqual(tb testing.TB, expected string, got string) {
 	tb.Helper()
 
@@ -45,3 +252,111 @@ func assertEqual(tb testing.TB, expected string, got string) {
 
 	tb.Errorf("unexpected result:\n"+comparison, expected, got)
 }
+
+func TestValidateAll(t *testing.T) {
+	t.Parallel()
+
+	valid := confirmation.New("ready?", confirmation.Undecided)
+
+	invalid := textinput.New("name:")
+	invalid.Template = "{{ Foo }}"
+
+	err := promptkit.ValidateAll(valid, invalid)
+	if err == nil {
+		t.Fatalf("validating an invalid prompt did not fail")
+	}
+
+	var validationErrs promptkit.ValidationErrors
+	if !errors.As(err, &validationErrs) || len(validationErrs) != 1 {
+		t.Fatalf("expected exactly one validation error, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "prompt 1 (*textinput.TextInput)") {
+		t.Errorf("error does not identify the invalid prompt: %v", err)
+	}
+
+	err = promptkit.ValidateAll(valid)
+	if err != nil {
+		t.Errorf("validating a valid prompt failed: %v", err)
+	}
+}
+
+func TestSetStrings(t *testing.T) { //nolint:paralleltest
+	promptkit.SetStrings(&promptkit.Strings{Yes: "Ja", No: "Nein"})
+	defer promptkit.SetStrings(nil)
+
+	if promptkit.CurrentStrings().FilterPrompt != promptkit.EnglishStrings().FilterPrompt {
+		t.Errorf("empty string did not fall back to English default")
+	}
+
+	c := confirmation.New("Fertig?", confirmation.No)
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m)
+
+	view := test.StripANSI(m.View())
+	if view != "Fertig?  Ja ▸Nein" {
+		t.Errorf("unexpected localized view %q", view)
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	output := &bytes.Buffer{}
+
+	c := confirmation.New("ready?", confirmation.Undecided)
+	m := confirmation.NewModel(c)
+
+	err := promptkit.Run(m, promptkit.WithInput(strings.NewReader("y")), promptkit.WithOutput(output))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	value, err := m.Value()
+	if err != nil || value != confirmation.Yes {
+		t.Errorf("expected yes, got %v (%v)", value, err)
+	}
+
+	if !strings.Contains(output.String(), "ready?") {
+		t.Errorf("prompt was not rendered to the output: %q", output.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = promptkit.Run(confirmation.NewModel(c), promptkit.WithInput(nil),
+		promptkit.WithOutput(io.Discard), promptkit.WithContext(ctx))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestSetColorProfile(t *testing.T) { //nolint:paralleltest
+	if p := promptkit.ResolveColorProfile(termenv.ANSI); p != termenv.ANSI {
+		t.Errorf("configured profile was not used: %v", p)
+	}
+
+	promptkit.SetColorProfile(termenv.Ascii)
+	defer promptkit.ResetColorProfile()
+
+	if p := promptkit.ResolveColorProfile(termenv.TrueColor); p != termenv.Ascii {
+		t.Errorf("forced profile was not used: %v", p)
+	}
+
+	c := confirmation.New("ready?", confirmation.Yes)
+	c.ColorProfile = termenv.TrueColor
+	m := confirmation.NewModel(c)
+
+	test.Run(t, m)
+
+	if view := m.View(); view != test.StripANSI(view) {
+		t.Errorf("forced ascii profile rendered colors: %q", view)
+	}
+
+	promptkit.ResetColorProfile()
+
+	if p := promptkit.ResolveColorProfile(termenv.TrueColor); p != termenv.TrueColor {
+		t.Errorf("reset did not restore the configured profile: %v", p)
+	}
+}
@@ -0,0 +1,40 @@
+package promptkit
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	randMu sync.Mutex
+	rng    = newDefaultRand()
+)
+
+func newDefaultRand() *rand.Rand {
+	return rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec
+}
+
+// SetRand sets the random number generator that is used by prompts for any
+// randomized behavior. Setting a generator with a fixed seed makes rendering
+// deterministic, which is useful in tests. If r is nil, a new generator that
+// is seeded with the current time is used.
+func SetRand(r *rand.Rand) {
+	randMu.Lock()
+	defer randMu.Unlock()
+
+	if r == nil {
+		r = newDefaultRand()
+	}
+
+	rng = r
+}
+
+// Intn returns a non-negative pseudo-random number in [0,n) from the generator
+// configured with SetRand. It is safe for concurrent use and panics if n <= 0.
+func Intn(n int) int {
+	randMu.Lock()
+	defer randMu.Unlock()
+
+	return rng.Intn(n)
+}
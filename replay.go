@@ -0,0 +1,22 @@
+package promptkit
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// ReplayKeys feeds the keys through the Update method of the model in order,
+// for example to deterministically reproduce a session that was recorded with
+// the RecordKeys option of a prompt. The model should already be initialized.
+// The updated model is returned.
+//
+// Commands that are returned by Update are not executed, so only the direct
+// effects of the keys are reproduced. Anything that a prompt handles through
+// the messages of its commands is skipped, for example a debounced filter
+// update, a clipboard paste, a ConfirmHold or Timeout and the tea.Quit of a
+// concluded prompt. Send the relevant messages to the model explicitly to
+// replay such behavior.
+func ReplayKeys(model tea.Model, keys []tea.KeyMsg) tea.Model {
+	for _, key := range keys {
+		model, _ = model.Update(key)
+	}
+
+	return model
+}
@@ -0,0 +1,128 @@
+package promptkit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RunOption configures how Run executes a prompt model.
+type RunOption func(*runConfig)
+
+type runConfig struct {
+	ctx        context.Context //nolint:containedctx
+	input      io.Reader
+	output     io.Writer
+	altScreen  bool
+	mouse      bool
+	lineEnding string
+	extra      []tea.ProgramOption
+}
+
+// WithInput sets the input reader of the program. By default, os.Stdin is
+// used. If it is nil, the program does not read any input.
+func WithInput(input io.Reader) RunOption {
+	return func(c *runConfig) {
+		c.input = input
+	}
+}
+
+// WithOutput sets the output writer of the program. By default, os.Stdout is
+// used.
+func WithOutput(output io.Writer) RunOption {
+	return func(c *runConfig) {
+		c.output = output
+	}
+}
+
+// WithAltScreen renders the model in the alternate screen buffer if enabled is
+// true. The final view is printed to the output after the program has ended.
+func WithAltScreen(enabled bool) RunOption {
+	return func(c *runConfig) {
+		c.altScreen = enabled
+	}
+}
+
+// WithMouse enables mouse events if enabled is true. Mouse events report
+// absolute screen positions, so the model is rendered in the alternate screen
+// buffer like with WithAltScreen such that its view starts in the top left
+// corner of the screen.
+func WithMouse(enabled bool) RunOption {
+	return func(c *runConfig) {
+		c.mouse = enabled
+	}
+}
+
+// WithContext stops the program as soon as the context is cancelled, in which
+// case the terminal is restored and Run returns the error of the context.
+func WithContext(ctx context.Context) RunOption {
+	return func(c *runConfig) {
+		c.ctx = ctx
+	}
+}
+
+// WithProgramOptions passes additional options to tea.NewProgram, for example
+// tea.WithoutSignalHandler. They are applied after the options that Run derives
+// from the other RunOptions such that they can override them.
+func WithProgramOptions(opts ...tea.ProgramOption) RunOption {
+	return func(c *runConfig) {
+		c.extra = append(c.extra, opts...)
+	}
+}
+
+// Run runs the model in a bubbletea program until it quits. It is used by the
+// RunPrompt methods of all prompts and can also be used to run custom prompt
+// models with the same conveniences. The result of the model has to be
+// retrieved from the model itself after Run returns.
+func Run(model tea.Model, opts ...RunOption) error {
+	config := runConfig{
+		ctx:    context.Background(),
+		input:  os.Stdin,
+		output: os.Stdout,
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	programOpts := []tea.ProgramOption{
+		tea.WithOutput(config.output), tea.WithInput(config.input), tea.WithContext(config.ctx),
+	}
+
+	if config.mouse {
+		// the view of an inline program may start in any row of the screen
+		config.altScreen = true
+	}
+
+	if config.altScreen {
+		programOpts = append(programOpts, tea.WithAltScreen())
+	}
+
+	if config.mouse {
+		programOpts = append(programOpts, tea.WithMouseCellMotion())
+	}
+
+	programOpts = append(programOpts, config.extra...)
+
+	_, err := tea.NewProgram(model, programOpts...).Run()
+	if config.ctx.Err() != nil {
+		return config.ctx.Err()
+	}
+
+	if err != nil {
+		return fmt.Errorf("running prompt: %w", err)
+	}
+
+	if config.altScreen {
+		// the final view was rendered in the alternate screen which is gone now
+		_, err = io.WriteString(config.output, ConvertLineEndings(model.View(), config.lineEnding))
+		if err != nil {
+			return fmt.Errorf("writing result: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package selection
 
 import (
 	"fmt"
+
+	"github.com/muesli/termenv"
 )
 
 // Choice represents a single choice. This type used as an input
@@ -10,6 +12,18 @@ type Choice[T any] struct {
 	idx    int
 	String string
 	Value  T
+
+	// profile is the resolved color profile of the selection for the default
+	// styles
+	profile termenv.Profile
+
+	// Quantity is the quantity of the choice that the user adjusted with the
+	// Increment and Decrement keys if WithQuantities is enabled.
+	Quantity int
+
+	// Recent is true if the choice was floated to the top of the list because
+	// it is one of the recently chosen items, see Selection.ShowRecent.
+	Recent bool
 }
 
 // Index returns the current index of the choice.
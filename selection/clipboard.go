@@ -0,0 +1,20 @@
+package selection
+
+import "time"
+
+// copiedIndicatorDuration is how long the indicator is shown after a choice
+// was copied to the clipboard.
+const copiedIndicatorDuration = 2 * time.Second
+
+// Clipboard writes text to the system clipboard. It is implemented by the
+// caller, for example with github.com/atotto/clipboard, such that the
+// selection does not depend on a specific clipboard implementation.
+type Clipboard interface {
+	WriteAll(text string) error
+}
+
+// copiedExpiredMsg hides the copied indicator unless another choice was copied
+// in the meantime.
+type copiedExpiredMsg struct {
+	generation int
+}
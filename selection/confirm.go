@@ -0,0 +1,74 @@
+package selection
+
+import (
+	"strings"
+
+	"github.com/erikgeiser/promptkit/confirmation"
+)
+
+// ThenConfirm runs the selection prompt and asks for a confirmation of the
+// chosen value afterwards. The String label of the chosen value replaces %s
+// in the prompt, for example "Deploy to %s?", and is appended in parentheses
+// if the prompt contains no %s, for example "Deploy? (production)". Use
+// ThenConfirmFunc for full control over the prompt. If either prompt is
+// aborted or fails, the zero value, false and the error are returned.
+func (s *Selection[T]) ThenConfirm(prompt string) (T, bool, error) {
+	return s.thenConfirm(func(choice *Choice[T]) string {
+		if strings.Contains(prompt, "%s") {
+			return strings.ReplaceAll(prompt, "%s", choice.String)
+		}
+
+		return prompt + " (" + choice.String + ")"
+	})
+}
+
+// ThenConfirmFunc works like ThenConfirm but derives the confirmation prompt
+// from the chosen value, for example:
+//
+//	sel.ThenConfirmFunc(func(env string) string {
+//		return "Deploy to " + env + "?"
+//	})
+//
+// The confirmation defaults to Yes and uses the same Input, Output,
+// ColorProfile, Theme, WrapMode, TrimBlankLines, AltScreen and EnableMouse
+// settings as the selection. The Abort, Interrupt and Select keys of the
+// selection's KeyMap also abort, interrupt and submit the confirmation.
+func (s *Selection[T]) ThenConfirmFunc(prompt func(T) string) (T, bool, error) {
+	return s.thenConfirm(func(choice *Choice[T]) string { return prompt(choice.Value) })
+}
+
+func (s *Selection[T]) thenConfirm(prompt func(*Choice[T]) string) (T, bool, error) {
+	var zeroValue T
+
+	m, err := s.run()
+	if err != nil {
+		return zeroValue, false, err
+	}
+
+	choice, err := m.ValueAsChoice()
+	if err != nil {
+		return zeroValue, false, err
+	}
+
+	c := confirmation.New(prompt(choice), confirmation.Yes, s.Theme)
+	c.Input = s.Input
+	c.Output = s.Output
+	c.ColorProfile = s.ColorProfile
+	c.WrapMode = s.WrapMode
+	c.TrimBlankLines = s.TrimBlankLines
+	c.AltScreen = s.AltScreen
+	c.EnableMouse = s.EnableMouse
+
+	if s.KeyMap != nil {
+		c.KeyMap.Abort = s.KeyMap.Abort
+		c.KeyMap.Interrupt = s.KeyMap.Interrupt
+		c.KeyMap.SubmitSelected = s.KeyMap.Select
+	}
+
+	confirmed, err := c.RunPrompt()
+	if err != nil {
+		return zeroValue, false, err
+	}
+
+	return choice.Value, confirmed, nil
+}
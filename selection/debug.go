@@ -0,0 +1,71 @@
+package selection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/internal/templates"
+)
+
+// DebugConfig returns a human-readable description of the effective
+// configuration of the selection prompt. It is intended to be included in bug
+// reports and its format is not stable.
+func (s *Selection[T]) DebugConfig() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Prompt: %q\n", s.Prompt)
+	fmt.Fprintf(&b, "Icon: %q\n", s.Icon)
+	fmt.Fprintf(&b, "ResultIcon: %q\n", s.ResultIcon)
+	fmt.Fprintf(&b, "Choices: %d\n", len(s.choices))
+	fmt.Fprintf(&b, "FilterPrompt: %q\n", s.FilterPrompt)
+	fmt.Fprintf(&b, "FilterPosition: %d\n", s.FilterPosition)
+	fmt.Fprintf(&b, "Filter: %t\n", s.Filter != nil)
+	fmt.Fprintf(&b, "FilterDebounce: %s\n", s.FilterDebounce)
+	fmt.Fprintf(&b, "FilterPlaceholder: %q\n", s.FilterPlaceholder)
+	fmt.Fprintf(&b, "PageSize: %d\n", s.PageSize)
+	fmt.Fprintf(&b, "Header: %q\n", s.Header)
+	fmt.Fprintf(&b, "LoopCursor: %t\n", s.LoopCursor)
+	fmt.Fprintf(&b, "CenterCursor: %t\n", s.CenterCursor)
+	fmt.Fprintf(&b, "EnableBack: %t\n", s.EnableBack)
+	fmt.Fprintf(&b, "ExactMatchConfirm: %t\n", s.ExactMatchConfirm)
+	fmt.Fprintf(&b, "EmptyEnterAborts: %t\n", s.EmptyEnterAborts)
+	fmt.Fprintf(&b, "DefaultChoice: %t\n", s.DefaultChoice != nil)
+	fmt.Fprintf(&b, "ResultContextLines: %d\n", s.ResultContextLines)
+	fmt.Fprintf(&b, "WithQuantities: %t\n", s.WithQuantities)
+	fmt.Fprintf(&b, "QuantityBounds: %t\n", s.QuantityBounds != nil)
+	fmt.Fprintf(&b, "ShowMatchCount: %t\n", s.ShowMatchCount)
+	fmt.Fprintf(&b, "Identity: %t\n", s.Identity != nil)
+	fmt.Fprintf(&b, "TooltipFunc: %t\n", s.TooltipFunc != nil)
+	fmt.Fprintf(&b, "MaxLabelWidth: %d\n", s.MaxLabelWidth)
+	fmt.Fprintf(&b, "Clipboard: %T\n", s.Clipboard)
+	fmt.Fprintf(&b, "RecentStore: %T\n", s.RecentStore)
+	fmt.Fprintf(&b, "ShowRecent: %d\n", s.ShowRecent)
+	fmt.Fprintf(&b, "Template: %s\n", templates.Name(s.Template, Templates))
+	fmt.Fprintf(&b, "ListTemplate: %s\n", templates.Name(s.ListTemplate,
+		map[string]string{"default": DefaultListTemplate}))
+	fmt.Fprintf(&b, "ResultTemplate: %s\n", templates.Name(s.ResultTemplate, ResultTemplates))
+	fmt.Fprintf(&b, "Theme: %+v\n", s.Theme)
+	fmt.Fprintf(&b, "ExtendedTemplateFuncs: %s\n", templates.FuncNames(s.ExtendedTemplateFuncs))
+	fmt.Fprintf(&b, "SelectedChoiceStyle: %t\n", s.SelectedChoiceStyle != nil)
+	fmt.Fprintf(&b, "UnselectedChoiceStyle: %t\n", s.UnselectedChoiceStyle != nil)
+	fmt.Fprintf(&b, "StyleFunc: %t\n", s.StyleFunc != nil)
+	fmt.Fprintf(&b, "FinalChoiceStyle: %t\n", s.FinalChoiceStyle != nil)
+	fmt.Fprintf(&b, "ResultDisplayFunc: %t\n", s.ResultDisplayFunc != nil)
+	fmt.Fprintf(&b, "KeyMap: %+v\n", s.KeyMap)
+	fmt.Fprintf(&b, "WrapMode: %t\n", s.WrapMode != nil)
+	fmt.Fprintf(&b, "TrimBlankLines: %t\n", s.TrimBlankLines)
+	fmt.Fprintf(&b, "AltScreen: %t\n", s.AltScreen)
+	fmt.Fprintf(&b, "EnableMouse: %t\n", s.EnableMouse)
+	fmt.Fprintf(&b, "InitialWidth: %d\n", s.InitialWidth)
+	fmt.Fprintf(&b, "InitialHeight: %d\n", s.InitialHeight)
+	fmt.Fprintf(&b, "Managed: %t\n", s.Managed)
+	fmt.Fprintf(&b, "ForwardUnhandledKeys: %t\n", s.ForwardUnhandledKeys)
+	fmt.Fprintf(&b, "LineEnding: %q\n", s.LineEnding)
+	fmt.Fprintf(&b, "ProgramOptions: %d\n", len(s.ProgramOptions))
+	fmt.Fprintf(&b, "Output: %T\n", s.Output)
+	fmt.Fprintf(&b, "Input: %T\n", s.Input)
+	fmt.Fprintf(&b, "ColorProfile: %d\n", promptkit.ResolveColorProfile(s.ColorProfile))
+
+	return b.String()
+}
@@ -14,21 +14,44 @@ func NewDefaultKeyMap() *KeyMap {
 		Up:          []string{"up"},
 		Select:      []string{"enter"},
 		Abort:       []string{"ctrl+c"},
+		Interrupt:   []string{},
 		ClearFilter: []string{"esc"},
+		Back:        []string{"esc"},
 		ScrollDown:  []string{"pgdown"},
 		ScrollUp:    []string{"pgup"},
+		Increment:   []string{"shift+right", "+"},
+		Decrement:   []string{"shift+left", "-"},
+		Yank:        []string{"y", "ctrl+y"},
 	}
 }
 
-// KeyMap defines the keys that trigger certain actions.
+// KeyMap defines the keys that trigger certain actions. The Abort keys abort
+// the prompt with promptkit.ErrAborted and the Interrupt keys abort it with
+// promptkit.ErrInterrupted such that the parent program can distinguish them.
+// By default, ctrl+c is an Abort key and no Interrupt keys are configured. The
+// Select keys confirm the only choice that matches the filter if there is
+// exactly one, the selected choice if there are multiple and do nothing if no
+// choice matches the filter. The Yank keys copy the selected choice to the
+// Clipboard without confirming it. They are only active if a Clipboard is
+// configured. The Increment and Decrement keys are only active if
+// WithQuantities is enabled. For both, keys that would type a character are
+// ignored while filtering is enabled such that they still reach the filter
+// input. By default, + and - therefore only adjust the quantity if the Filter
+// is nil while shift+right and shift+left, which the filter input does not
+// use, always do.
 type KeyMap struct {
 	Down        []string
 	Up          []string
 	Select      []string
 	Abort       []string
+	Interrupt   []string
 	ClearFilter []string
+	Back        []string
 	ScrollDown  []string
 	ScrollUp    []string
+	Increment   []string
+	Decrement   []string
+	Yank        []string
 }
 
 func keyMatches(key tea.KeyMsg, mapping []string) bool {
@@ -57,8 +80,8 @@ func validateKeyMap(km *KeyMap) error {
 		return fmt.Errorf("no select key")
 	}
 
-	if len(km.Abort) == 0 {
-		return fmt.Errorf("no abort key")
+	if len(km.Abort) == 0 && len(km.Interrupt) == 0 {
+		return fmt.Errorf("no abort or interrupt key")
 	}
 
 	return nil
@@ -2,18 +2,29 @@ package selection
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"math"
 	"os"
+	"strconv"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/internal/keys"
+	"github.com/erikgeiser/promptkit/internal/region"
+	"github.com/muesli/reflow/truncate"
 	"github.com/muesli/termenv"
 	"golang.org/x/term"
 )
 
+// doubleClickInterval is the maximum duration between two clicks on the same
+// choice such that they are considered a double-click.
+const doubleClickInterval = 500 * time.Millisecond
+
 // Model implements the bubbletea.Model for a selection prompt.
 type Model[T any] struct {
 	*Selection[T]
@@ -30,15 +41,46 @@ type Model[T any] struct {
 	currentChoices []*Choice[T]
 	// number of available choices after filtering
 	availableChoices int
+	// all choices that match filteredFor, the filter text they were filtered
+	// with, such that scrolling only slices the next page instead of
+	// filtering all choices again
+	filtered      []*Choice[T]
+	filteredFor   string
+	filteredValid bool
 	// index of current selection in currentChoices slice
 	currentIdx        int
 	scrollOffset      int
 	width             int
 	height            int
 	tmpl              *template.Template
+	listTmpl          *template.Template
 	resultTmpl        *template.Template
 	requestedPageSize int
 
+	lastClickedChoice *Choice[T]
+	lastClickTime     time.Time
+
+	// choiceRegions holds where the choices were rendered in the last view
+	// for mouse hit-testing
+	choiceRegions []region.Region
+
+	// whether the user moved the cursor or scrolled
+	navigated bool
+
+	// filterGeneration is incremented on each filter change such that only the
+	// latest debounce tick applies the filter
+	filterGeneration int
+	filterPending    bool
+
+	// copiedGeneration is incremented on each copy such that only the latest
+	// expiry tick hides the copied indicator
+	copiedGeneration int
+	copied           bool
+
+	// recent holds the items of the RecentStore as loaded by Init such that
+	// SetChoices can float the recent choices without reading it again
+	recent []string
+
 	quitting bool
 }
 
@@ -53,36 +95,63 @@ func NewModel[T any](selection *Selection[T]) *Model[T] {
 
 // Init initializes the selection prompt model.
 func (m *Model[T]) Init() tea.Cmd {
-	m.reindexChoices()
-
 	if len(m.choices) == 0 {
 		m.Err = fmt.Errorf("no choices provided")
 
-		return tea.Quit
+		return m.quit()
+	}
+
+	m.recent, m.Err = m.loadRecent()
+	if m.Err != nil {
+		return m.quit()
 	}
 
+	m.floatRecentChoices()
+	m.reindexChoices()
+
 	if m.Template == "" {
 		m.Err = fmt.Errorf("empty template")
 
-		return tea.Quit
+		return m.quit()
 	}
 
 	m.tmpl, m.Err = m.initTemplate()
 	if m.Err != nil {
-		return tea.Quit
+		return m.quit()
+	}
+
+	m.listTmpl, m.Err = m.initListTemplate()
+	if m.Err != nil {
+		return m.quit()
 	}
 
 	m.resultTmpl, m.Err = m.initResultTemplate()
 	if m.Err != nil {
-		return tea.Quit
+		return m.quit()
 	}
 
 	m.filterInput = m.initFilterInput()
 
+	if m.WithQuantities {
+		m.initQuantities()
+	}
+
 	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
 
 	m.requestedPageSize = m.PageSize
 
+	m.selectDefaultChoice()
+
+	if m.InitialWidth > 0 || m.InitialHeight > 0 {
+		m.resize(m.InitialWidth, m.InitialHeight)
+
+		return textinput.Blink
+	}
+
+	if m.Managed {
+		return textinput.Blink
+	}
+
 	// try to get an initial terminal size in order to avoid initial overdrawing
 	// which can cause ugly glitches on some terminals
 	outputFile, ok := m.Output.(*os.File)
@@ -97,34 +166,82 @@ func (m *Model[T]) Init() tea.Cmd {
 }
 
 func (m *Model[T]) initTemplate() (*template.Template, error) {
-	tmpl := template.New("view")
-	tmpl.Funcs(termenv.TemplateFuncs(m.ColorProfile))
-	tmpl.Funcs(m.ExtendedTemplateFuncs)
-	tmpl.Funcs(promptkit.UtilFuncMap())
-	tmpl.Funcs(template.FuncMap{
-		"IsScrollDownHintPosition": func(idx int) bool {
-			return m.canScrollDown() && (idx == len(m.currentChoices)-1)
-		},
-		"IsScrollUpHintPosition": func(idx int) bool {
-			return m.canScrollUp() && idx == 0 && m.scrollOffset > 0
-		},
-		"Selected": func(c *Choice[T]) string {
-			if m.SelectedChoiceStyle == nil {
-				return c.String
-			}
+	return promptkit.ParseTemplate("view", m.Template, m.viewTemplateFuncMaps()...)
+}
 
-			return m.SelectedChoiceStyle(c)
-		},
-		"Unselected": func(c *Choice[T]) string {
-			if m.UnselectedChoiceStyle == nil {
-				return c.String
-			}
+func (m *Model[T]) initListTemplate() (*template.Template, error) {
+	listTemplate := m.ListTemplate
+	if listTemplate == "" {
+		listTemplate = DefaultListTemplate
+	}
 
-			return m.UnselectedChoiceStyle(c)
+	return promptkit.ParseTemplate("list", listTemplate, m.viewTemplateFuncMaps()...)
+}
+
+func (m *Model[T]) viewTemplateFuncMaps() []template.FuncMap {
+	return []template.FuncMap{
+		termenv.TemplateFuncs(m.colorProfile()),
+		promptkit.UtilFuncMap(),
+		m.themeFuncs(),
+		m.ExtendedTemplateFuncs,
+		{
+			"IsScrollDownHintPosition": func(idx int) bool {
+				return m.canScrollDown() && (idx == len(m.currentChoices)-1)
+			},
+			"IsScrollUpHintPosition": func(idx int) bool {
+				return m.canScrollUp() && idx == 0 && m.scrollOffset > 0
+			},
+			"IsDefault": m.isDefault,
+			"Selected": func(c *Choice[T]) string {
+				if m.SelectedChoiceStyle == nil {
+					return m.markChoice(c, m.styleRow(c, true, m.truncateLabel(c.String)))
+				}
+
+				return m.markChoice(c, m.styleRow(c, true, m.truncateLabel(m.SelectedChoiceStyle(c))))
+			},
+			"Unselected": func(c *Choice[T]) string {
+				if m.UnselectedChoiceStyle == nil {
+					return m.markChoice(c, m.styleRow(c, false, m.truncateLabel(c.String)))
+				}
+
+				return m.markChoice(c, m.styleRow(c, false, m.truncateLabel(m.UnselectedChoiceStyle(c))))
+			},
 		},
-	})
+	}
+}
 
-	return tmpl.Parse(m.Template)
+// isDefault returns whether the choice is a default choice.
+func (m *Model[T]) isDefault(c *Choice[T]) bool {
+	return m.DefaultChoice != nil && m.DefaultChoice(c.Value)
+}
+
+// selectDefaultChoice moves the cursor to the first default choice.
+func (m *Model[T]) selectDefaultChoice() {
+	if idx, ok := m.filteredIndexFunc(m.isDefault); ok {
+		m.moveCursorTo(idx)
+	}
+}
+
+// truncateLabel truncates a rendered choice to MaxLabelWidth with an ellipsis.
+func (m *Model[T]) truncateLabel(rendered string) string {
+	if m.MaxLabelWidth <= 0 {
+		return rendered
+	}
+
+	return truncate.StringWithTail(rendered, uint(m.MaxLabelWidth), "…")
+}
+
+// styleRow applies the StyleFunc to an already rendered choice.
+func (m *Model[T]) styleRow(c *Choice[T], highlighted bool, rendered string) string {
+	if m.StyleFunc == nil {
+		return rendered
+	}
+
+	return m.StyleFunc(c.Value, RowState{
+		Highlighted: highlighted,
+		Checked:     m.WithQuantities && c.Quantity != 0,
+		Rendered:    rendered,
+	})
 }
 
 func (m *Model[T]) initResultTemplate() (*template.Template, error) {
@@ -132,21 +249,39 @@ func (m *Model[T]) initResultTemplate() (*template.Template, error) {
 		return nil, nil //nolint:nilnil
 	}
 
-	tmpl := template.New("result")
-	tmpl.Funcs(termenv.TemplateFuncs(m.ColorProfile))
-	tmpl.Funcs(m.ExtendedTemplateFuncs)
-	tmpl.Funcs(promptkit.UtilFuncMap())
-	tmpl.Funcs(template.FuncMap{
-		"Final": func(c *Choice[T]) string {
-			if m.FinalChoiceStyle == nil {
-				return c.String
-			}
-
-			return m.FinalChoiceStyle(c)
+	return promptkit.ParseTemplate("result", m.ResultTemplate,
+		termenv.TemplateFuncs(m.colorProfile()),
+		promptkit.UtilFuncMap(),
+		m.themeFuncs(),
+		m.ExtendedTemplateFuncs,
+		template.FuncMap{
+			"Final": func(c *Choice[T]) string {
+				if m.ResultDisplayFunc != nil {
+					display := *c
+					display.String = m.ResultDisplayFunc(c.Value)
+					c = &display
+				}
+
+				if m.FinalChoiceStyle == nil {
+					return c.String
+				}
+
+				return m.FinalChoiceStyle(c)
+			},
 		},
-	})
+	)
+}
+
+// themeFuncs returns the template functions of the Theme in which empty colors
+// fall back to the colors of the built-in templates.
+func (m *Model[T]) themeFuncs() template.FuncMap {
+	return m.Theme.WithDefaults(defaultTheme).TemplateFuncs(m.colorProfile())
+}
 
-	return tmpl.Parse(m.ResultTemplate)
+// colorProfile returns the configured ColorProfile unless another profile was
+// forced with promptkit.SetColorProfile.
+func (m *Model[T]) colorProfile() termenv.Profile {
+	return promptkit.ResolveColorProfile(m.ColorProfile)
 }
 
 func (m *Model[T]) initFilterInput() textinput.Model {
@@ -156,6 +291,11 @@ func (m *Model[T]) initFilterInput() textinput.Model {
 	filterInput.PlaceholderStyle = m.FilterInputPlaceholderStyle
 	filterInput.Cursor.Style = m.FilterInputCursorStyle
 	filterInput.Placeholder = m.FilterPlaceholder
+
+	if filterInput.Placeholder == "" {
+		filterInput.Placeholder = promptkit.CurrentStrings().FilterPlaceholder
+	}
+
 	filterInput.Width = 80
 	filterInput.Focus()
 
@@ -192,52 +332,278 @@ func (m *Model[T]) Value() (T, error) {
 	return choice.Value, nil
 }
 
+// QuantityChoices returns all choices with a non-zero quantity in their
+// original order. It is only meaningful if WithQuantities is enabled. Unlike
+// Value, it also returns the partial selection alongside the error if the
+// prompt was aborted with promptkit.ErrAborted or promptkit.ErrInterrupted
+// such that callers can decide whether to use it. For all other errors, no
+// choices are returned.
+func (m *Model[T]) QuantityChoices() ([]*Choice[T], error) {
+	if m.Err != nil && !errors.Is(m.Err, promptkit.ErrAborted) &&
+		!errors.Is(m.Err, promptkit.ErrInterrupted) {
+		return nil, m.Err
+	}
+
+	choices := []*Choice[T]{}
+
+	for _, choice := range m.choices {
+		if choice.Quantity != 0 {
+			choices = append(choices, choice)
+		}
+	}
+
+	return choices, m.Err
+}
+
+// Quantities returns the quantities of all choices of the model with a
+// non-zero quantity. It is only meaningful if WithQuantities is enabled. Like
+// QuantityChoices, it returns the partial quantities alongside the error if
+// the prompt was aborted.
+func Quantities[T comparable](m *Model[T]) (map[T]int, error) {
+	choices, err := m.QuantityChoices()
+	if choices == nil {
+		return nil, err
+	}
+
+	quantities := make(map[T]int, len(choices))
+
+	for _, choice := range choices {
+		quantities[choice.Value] += choice.Quantity
+	}
+
+	return quantities, err
+}
+
+// initQuantities resets the quantity of each choice to its minimum such that
+// quantities from a previous run of the model do not carry over.
+func (m *Model[T]) initQuantities() {
+	for _, choice := range m.choices {
+		choice.Quantity, _ = m.quantityBounds(choice)
+	}
+}
+
+// clampQuantities moves the quantity of each choice into its bounds.
+func (m *Model[T]) clampQuantities() {
+	for _, choice := range m.choices {
+		lower, upper := m.quantityBounds(choice)
+		choice.Quantity = min(upper, max(lower, choice.Quantity))
+	}
+}
+
+func (m *Model[T]) quantityBounds(choice *Choice[T]) (int, int) {
+	if m.QuantityBounds == nil {
+		return 0, math.MaxInt
+	}
+
+	return m.QuantityBounds(choice.Value)
+}
+
+// adjustQuantity changes the quantity of the currently selected choice by delta
+// within its bounds.
+func (m *Model[T]) adjustQuantity(delta int) {
+	if m.currentIdx < 0 || m.currentIdx >= len(m.currentChoices) {
+		return
+	}
+
+	choice := m.currentChoices[m.currentIdx]
+	lower, upper := m.quantityBounds(choice)
+
+	if (delta > 0 && choice.Quantity >= upper) || (delta < 0 && choice.Quantity <= lower) {
+		return
+	}
+
+	choice.Quantity = min(upper, max(lower, choice.Quantity+delta))
+}
+
+// DoneMsg is emitted by a Managed model when the prompt concludes or aborts
+// instead of quitting the program. Value and Err correspond to the result of
+// Model.Value. Afterwards, the model ignores all messages.
+type DoneMsg[T any] struct {
+	Value T
+	Err   error
+}
+
+// ChoicesMsg replaces the choices of a running selection prompt as described
+// in Model.SetChoices, for example when the choices are streamed in.
+type ChoicesMsg[T any] struct {
+	Choices []T
+}
+
+// SetChoices replaces the choices of the selection. The selected choice and
+// the quantities are carried over to the new choices using the Identity of
+// the Selection or, if Identity is nil, using the index of the choices. If the
+// selected choice is not present anymore, the first choice is selected.
+func (m *Model[T]) SetChoices(values []T) {
+	previous := m.choices
+
+	var selected *Choice[T]
+	if m.currentIdx >= 0 && m.currentIdx < len(m.currentChoices) {
+		selected = m.currentChoices[m.currentIdx]
+	}
+
+	m.choices = asChoices(values)
+	m.filteredValid = false
+	m.floatRecentChoices()
+	m.reindexChoices()
+
+	selectedIdx := -1
+	key := m.identityKey
+
+	previousByKey := make(map[string]*Choice[T], len(previous))
+	for _, choice := range previous {
+		previousByKey[key(choice)] = choice
+	}
+
+	for _, choice := range m.choices {
+		old, ok := previousByKey[key(choice)]
+		if !ok {
+			continue
+		}
+
+		choice.Quantity = old.Quantity
+
+		if old == selected {
+			selectedIdx = choice.Index()
+		}
+	}
+
+	if m.WithQuantities {
+		m.clampQuantities()
+	}
+
+	// the page size was clamped to the previous number of choices
+	if m.height > 0 {
+		m.forceUpdatePageSizeForHeight()
+	} else {
+		m.PageSize = m.requestedPageSize
+	}
+
+	m.currentIdx = 0
+	m.scrollOffset = 0
+	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+
+	if selectedIdx >= 0 {
+		selected := m.choices[selectedIdx]
+
+		if idx, ok := m.filteredIndexFunc(func(c *Choice[T]) bool { return c == selected }); ok {
+			m.moveCursorTo(idx)
+		}
+	}
+}
+
+// identityKey returns the key by which a choice is matched in SetChoices.
+func (m *Model[T]) identityKey(choice *Choice[T]) string {
+	if m.Identity == nil {
+		return strconv.Itoa(choice.Index())
+	}
+
+	return m.Identity(choice.Value)
+}
+
 // Update updates the model based on the received message.
 func (m *Model[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.Managed && m.quitting {
+		// the parent program was already notified with a DoneMsg
+		return m, nil
+	}
+
 	if m.Err != nil {
-		return m, tea.Quit
+		return m, m.quit()
 	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		keys.Record(m.RecordKeys, msg)
+
 		switch {
+		case keyMatches(msg, m.KeyMap.Interrupt):
+			m.Err = promptkit.ErrInterrupted
+			m.quitting = true
+
+			return m, m.quit()
 		case keyMatches(msg, m.KeyMap.Abort):
 			m.Err = promptkit.ErrAborted
 			m.quitting = true
 
-			return m, tea.Quit
+			return m, m.quit()
 		case keyMatches(msg, m.KeyMap.Select):
-			if len(m.currentChoices) == 0 {
-				return m, nil
-			}
-
+			return m.confirm()
+		case m.EnableBack && keyMatches(msg, m.KeyMap.Back) &&
+			!(keyMatches(msg, m.KeyMap.ClearFilter) && m.filterInput.Value() != ""):
+			m.Err = ErrBack
 			m.quitting = true
 
-			return m, tea.Quit
+			return m, m.quit()
 		case keyMatches(msg, m.KeyMap.ClearFilter):
 			m.filterInput.Reset()
+			m.filterPending = false
 			m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
 		case keyMatches(msg, m.KeyMap.Down):
+			m.navigated = true
 			m.cursorDown()
+
+			if m.CenterCursor {
+				m.centerCursor()
+			}
 		case keyMatches(msg, m.KeyMap.Up):
+			m.navigated = true
 			m.cursorUp()
+
+			if m.CenterCursor {
+				m.centerCursor()
+			}
 		case keyMatches(msg, m.KeyMap.ScrollDown):
+			m.navigated = true
 			m.scrollDown()
 		case keyMatches(msg, m.KeyMap.ScrollUp):
+			m.navigated = true
 			m.scrollUp()
+		case m.WithQuantities && keyMatches(msg, m.KeyMap.Increment) &&
+			(m.Filter == nil || msg.Type != tea.KeyRunes):
+			m.adjustQuantity(1)
+		case m.WithQuantities && keyMatches(msg, m.KeyMap.Decrement) &&
+			(m.Filter == nil || msg.Type != tea.KeyRunes):
+			m.adjustQuantity(-1)
+		case m.Clipboard != nil && keyMatches(msg, m.KeyMap.Yank) &&
+			(m.Filter == nil || msg.Type != tea.KeyRunes):
+			return m.yank()
+		case m.ForwardUnhandledKeys && !(m.Filter != nil && keys.HandledByInput(m.filterInput.KeyMap, msg)):
+			return m, promptkit.ForwardKey(msg)
 		default:
 			return m.updateFilter(msg)
 		}
 
 		return m, nil
+	case tea.MouseMsg:
+		if m.EnableMouse {
+			return m.updateMouse(msg)
+		}
+	case ChoicesMsg[T]:
+		m.SetChoices(msg.Choices)
+	case ScrollToMsg:
+		m.ScrollTo(msg.Offset)
+	case ScrollByMsg:
+		m.ScrollBy(msg.Lines)
+	case filterDebounceMsg:
+		if m.filterPending && msg.generation == m.filterGeneration {
+			m.applyFilter()
+		}
+	case copiedExpiredMsg:
+		if msg.generation == m.copiedGeneration {
+			m.copied = false
+		}
 	case tea.WindowSizeMsg:
 		m.resize(msg.Width, msg.Height)
 
+		if m.Managed {
+			return m, nil
+		}
+
 		return m, tea.ClearScrollArea
 	case error:
 		m.Err = msg
 
-		return m, tea.Quit
+		return m, m.quit()
 	}
 
 	var cmd tea.Cmd
@@ -245,6 +611,40 @@ func (m *Model[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// confirm handles the Select keys deterministically: If no choice matches the
+// filter, nothing happens. If exactly one choice matches the filter, it is
+// confirmed. Otherwise, the selected choice is confirmed. EmptyEnterAborts
+// and ExactMatchConfirm take precedence as documented.
+func (m *Model[T]) confirm() (*Model[T], tea.Cmd) {
+	if m.filterPending {
+		m.applyFilter()
+	}
+
+	if m.availableChoices == 0 || len(m.currentChoices) == 0 {
+		return m, nil
+	}
+
+	if m.EmptyEnterAborts && !m.navigated && m.filterInput.Value() == "" {
+		m.Err = promptkit.ErrAborted
+		m.quitting = true
+
+		return m, m.quit()
+	}
+
+	switch idx, exactMatch := m.exactMatchIndex(); {
+	case m.ExactMatchConfirm && exactMatch:
+		m.moveCursorTo(idx)
+	case m.availableChoices == 1:
+		m.moveCursorTo(0)
+	default: // confirm the selected choice
+	}
+
+	m.quitting = true
+	m.Err = m.addRecentChoice()
+
+	return m, m.quit()
+}
+
 func (m *Model[T]) resize(width int, height int) {
 	m.width = zeroAwareMin(width, m.MaxWidth)
 
@@ -255,6 +655,10 @@ func (m *Model[T]) resize(width int, height int) {
 }
 
 func (m *Model[T]) forceUpdatePageSizeForHeight() {
+	// keep the selected choice, e.g. the default choice, selected with the
+	// new page size
+	defer m.restoreCursor(m.scrollOffset + m.currentIdx)
+
 	maxAcceptablePageSize := len(m.choices)
 	if m.requestedPageSize != 0 {
 		maxAcceptablePageSize = min(len(m.choices), m.requestedPageSize)
@@ -266,7 +670,7 @@ func (m *Model[T]) forceUpdatePageSizeForHeight() {
 	m.scrollOffset = 0
 	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
 
-	if lipgloss.Height(m.View()) < m.height {
+	if promptkit.MeasureHeight(m.View(), m.width) < m.height {
 		return
 	}
 
@@ -274,7 +678,7 @@ func (m *Model[T]) forceUpdatePageSizeForHeight() {
 	for m.PageSize = 1; m.PageSize <= maxAcceptablePageSize; m.PageSize++ {
 		m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
 
-		if lipgloss.Height(m.View()) >= m.height {
+		if promptkit.MeasureHeight(m.View(), m.width) >= m.height {
 			m.PageSize--
 			m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
 
@@ -286,6 +690,46 @@ func (m *Model[T]) forceUpdatePageSizeForHeight() {
 	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
 }
 
+// restoreCursor moves the cursor to the choice at the given index within the
+// choices that match the filter or to the last one if there are less choices.
+func (m *Model[T]) restoreCursor(idx int) {
+	if m.availableChoices == 0 {
+		return
+	}
+
+	m.moveCursorTo(min(idx, m.availableChoices-1))
+}
+
+// yank copies the selected choice to the Clipboard and shows the copied
+// indicator for a moment.
+func (m *Model[T]) yank() (*Model[T], tea.Cmd) {
+	choice, err := m.ValueAsChoice()
+	if err != nil {
+		return m, nil
+	}
+
+	err = m.Clipboard.WriteAll(choice.String)
+	if err != nil {
+		m.Err = fmt.Errorf("copy to clipboard: %w", err)
+
+		return m, m.quit()
+	}
+
+	m.copiedGeneration++
+	m.copied = true
+	generation := m.copiedGeneration
+
+	return m, tea.Tick(copiedIndicatorDuration, func(time.Time) tea.Msg {
+		return copiedExpiredMsg{generation: generation}
+	})
+}
+
+// filterDebounceMsg applies the filter after FilterDebounce if no other
+// filter change happened in the meantime.
+type filterDebounceMsg struct {
+	generation int
+}
+
 func (m *Model[T]) updateFilter(msg tea.Msg) (*Model[T], tea.Cmd) {
 	if m.Filter == nil {
 		return m, nil
@@ -296,13 +740,96 @@ func (m *Model[T]) updateFilter(msg tea.Msg) (*Model[T], tea.Cmd) {
 	var cmd tea.Cmd
 	m.filterInput, cmd = m.filterInput.Update(msg)
 
-	if m.filterInput.Value() != previousFilter {
-		m.currentIdx = 0
-		m.scrollOffset = 0
-		m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+	if m.filterInput.Value() == previousFilter {
+		return m, cmd
 	}
 
-	return m, cmd
+	if m.FilterDebounce <= 0 {
+		m.applyFilter()
+
+		return m, cmd
+	}
+
+	m.filterGeneration++
+	m.filterPending = true
+	generation := m.filterGeneration
+
+	return m, tea.Batch(cmd, tea.Tick(m.FilterDebounce, func(time.Time) tea.Msg {
+		return filterDebounceMsg{generation: generation}
+	}))
+}
+
+// applyFilter filters the choices with the current filter text and resets the
+// cursor.
+func (m *Model[T]) applyFilter() {
+	m.filterPending = false
+	m.currentIdx = 0
+	m.scrollOffset = 0
+	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+}
+
+func (m *Model[T]) updateMouse(msg tea.MouseMsg) (*Model[T], tea.Cmd) {
+	switch msg.Type {
+	case tea.MouseWheelUp:
+		m.scrollUp()
+	case tea.MouseWheelDown:
+		m.scrollDown()
+	case tea.MouseLeft:
+		idx := m.choiceIndexAt(msg.Y)
+		if idx < 0 {
+			return m, nil
+		}
+
+		choice := m.currentChoices[idx]
+		doubleClick := m.lastClickedChoice == choice &&
+			time.Since(m.lastClickTime) <= doubleClickInterval
+
+		m.currentIdx = idx
+		m.navigated = true
+		m.lastClickedChoice = choice
+		m.lastClickTime = time.Now()
+
+		if doubleClick {
+			return m.confirm()
+		}
+	default: // do nothing
+	}
+
+	return m, nil
+}
+
+// choiceIndexAt returns the index of the choice in currentChoices that is
+// rendered in the given line of the view or -1 if no choice is rendered there.
+// The lines are looked up in the regions that were recorded when the choices
+// were last rendered with Selected or Unselected, so other text in the view
+// that contains a choice's string is never mistaken for it.
+func (m *Model[T]) choiceIndexAt(y int) int {
+	// render the view to record the regions of the current state
+	_ = m.View()
+
+	for _, r := range m.choiceRegions {
+		if !r.ContainsRow(y) {
+			continue
+		}
+
+		for i, choice := range m.currentChoices {
+			if choice.idx == r.ID {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// markChoice marks the rendered choice for mouse hit-testing if EnableMouse is
+// set.
+func (m *Model[T]) markChoice(c *Choice[T], rendered string) string {
+	if !m.EnableMouse {
+		return rendered
+	}
+
+	return region.Mark(c.idx, rendered)
 }
 
 // View renders the selection prompt.
@@ -325,27 +852,85 @@ func (m *Model[T]) View() string {
 		return ""
 	}
 
-	err := m.tmpl.Execute(viewBuffer, map[string]interface{}{
-		"Prompt":        m.Prompt,
-		"IsFiltered":    m.Filter != nil,
-		"FilterPrompt":  m.FilterPrompt,
-		"FilterInput":   m.filterInput.View(),
-		"Choices":       m.currentChoices,
-		"NChoices":      len(m.currentChoices),
-		"SelectedIndex": m.currentIdx,
-		"PageSize":      m.PageSize,
-		"IsPaged":       m.PageSize > 0 && len(m.currentChoices) > m.PageSize,
-		"AllChoices":    m.choices,
-		"NAllChoices":   len(m.choices),
-		"TerminalWidth": m.width,
-	})
+	err := m.tmpl.Execute(viewBuffer, m.templateData())
+	if err != nil {
+		m.Err = err
+
+		return "Template Error: " + err.Error()
+	}
+
+	view, regions := region.Extract(m.wrap(viewBuffer.String()))
+	m.choiceRegions = regions
+
+	return view
+}
+
+// ViewList renders only the list of choices using the ListTemplate, without
+// the prompt and the filter input. This is useful when the selection is
+// embedded in a larger UI that renders these elements itself.
+func (m *Model[T]) ViewList() string {
+	// avoid panics if Quit is sent during Init
+	if m.listTmpl == nil {
+		return ""
+	}
+
+	viewBuffer := &bytes.Buffer{}
+
+	err := m.listTmpl.Execute(viewBuffer, m.templateData())
 	if err != nil {
 		m.Err = err
 
 		return "Template Error: " + err.Error()
 	}
 
-	return m.wrap(viewBuffer.String())
+	view, _ := region.Extract(m.wrap(viewBuffer.String()))
+
+	return view
+}
+
+func (m *Model[T]) templateData() map[string]interface{} {
+	return map[string]interface{}{
+		"Prompt":            m.Prompt,
+		"Icon":              m.Icon,
+		"IsFiltered":        m.Filter != nil,
+		"FilterPrompt":      m.FilterPrompt,
+		"FilterAtBottom":    m.FilterPosition == FilterBottom,
+		"FilterInput":       m.filterInput.View(),
+		"FilterPlaceholder": m.filterInput.Placeholder,
+		"Header":            m.Header,
+		"Choices":           m.currentChoices,
+		"NChoices":          len(m.currentChoices),
+		"SelectedIndex":     m.currentIdx,
+		"PageSize":          m.PageSize,
+		"IsPaged":           m.PageSize > 0 && len(m.currentChoices) > m.PageSize,
+		"AllChoices":        m.choices,
+		"NAllChoices":       len(m.choices),
+		"NMatchedChoices":   m.availableChoices,
+		"TotalCount":        len(m.choices),
+		"FilteredCount":     m.availableChoices,
+		"IsNarrowed":        m.filterInput.Value() != "",
+		"Narrowing":         m.filterPending,
+		"ShowMatchCount":    m.ShowMatchCount,
+		"TerminalWidth":     m.width,
+		"WithQuantities":    m.WithQuantities,
+		"Tooltip":           m.tooltip(),
+		"Copied":            m.copied,
+	}
+}
+
+// tooltip returns the first line of the tooltip for the currently selected
+// choice truncated to the terminal width.
+func (m *Model[T]) tooltip() string {
+	if m.TooltipFunc == nil || m.currentIdx < 0 || m.currentIdx >= len(m.currentChoices) {
+		return ""
+	}
+
+	tooltip, _, _ := strings.Cut(m.TooltipFunc(m.currentChoices[m.currentIdx].Value), "\n")
+	if m.width == 0 {
+		return tooltip
+	}
+
+	return truncate.String(tooltip, uint(m.width))
 }
 
 func (m *Model[T]) resultView() (string, error) {
@@ -364,12 +949,24 @@ func (m *Model[T]) resultView() (string, error) {
 		return "", err
 	}
 
+	contextChoices, contextSelectedIdx := m.resultContext(choice)
+
+	quantityChoices, err := m.QuantityChoices()
+	if err != nil {
+		return "", err
+	}
+
 	err = m.resultTmpl.Execute(viewBuffer, map[string]interface{}{
-		"FinalChoice":   choice,
-		"Prompt":        m.Prompt,
-		"AllChoices":    m.choices,
-		"NAllChoices":   len(m.choices),
-		"TerminalWidth": m.width,
+		"FinalChoice":          choice,
+		"ContextChoices":       contextChoices,
+		"ContextSelectedIndex": contextSelectedIdx,
+		"WithQuantities":       m.WithQuantities,
+		"QuantityChoices":      quantityChoices,
+		"Prompt":               m.Prompt,
+		"ResultIcon":           m.ResultIcon,
+		"AllChoices":           m.choices,
+		"NAllChoices":          len(m.choices),
+		"TerminalWidth":        m.width,
 	})
 	if err != nil {
 		return "", fmt.Errorf("execute confirmation template: %w", err)
@@ -378,7 +975,40 @@ func (m *Model[T]) resultView() (string, error) {
 	return viewBuffer.String(), nil
 }
 
+// resultContext returns the final choice surrounded by up to
+// ResultContextLines choices as well as the index of the final choice in the
+// returned slice.
+func (m *Model[T]) resultContext(choice *Choice[T]) ([]*Choice[T], int) {
+	if m.ResultContextLines <= 0 {
+		return nil, 0
+	}
+
+	start := max(0, choice.Index()-m.ResultContextLines)
+	end := min(len(m.choices), choice.Index()+m.ResultContextLines+1)
+
+	return m.choices[start:end], choice.Index() - start
+}
+
+// quit returns tea.Quit unless the prompt is managed by a parent program, in
+// which case it returns a command that emits a DoneMsg.
+func (m *Model[T]) quit() tea.Cmd {
+	if m.Managed {
+		m.quitting = true
+		value, err := m.Value()
+
+		return func() tea.Msg {
+			return DoneMsg[T]{Value: value, Err: err}
+		}
+	}
+
+	return tea.Quit
+}
+
 func (m *Model[T]) wrap(text string) string {
+	if m.TrimBlankLines {
+		text = promptkit.TrimBlankLines(text)
+	}
+
 	if m.WrapMode == nil {
 		return text
 	}
@@ -386,28 +1016,119 @@ func (m *Model[T]) wrap(text string) string {
 	return m.WrapMode(text, m.width)
 }
 
+// filteredAndPagedChoices returns the choices on the current page as well as
+// the number of choices that match the filter. The choices are only filtered
+// again if the filter text changed, such that scrolling through large lists
+// does not depend on the total number of choices.
 func (m *Model[T]) filteredAndPagedChoices() ([]*Choice[T], int) {
-	choices := []*Choice[T]{}
+	m.refreshFiltered()
 
-	var available, ignored int
+	if m.PageSize <= 0 {
+		return m.filtered, len(m.filtered)
+	}
 
-	for _, choice := range m.choices {
-		if m.Filter != nil && !m.Filter(m.filterInput.Value(), choice) {
-			continue
+	start := min(m.scrollOffset, len(m.filtered))
+	end := min(start+m.PageSize, len(m.filtered))
+
+	return m.filtered[start:end:end], len(m.filtered)
+}
+
+// refreshFiltered filters the choices again if the filter text changed since
+// they were last filtered.
+func (m *Model[T]) refreshFiltered() {
+	filter := m.filterInput.Value()
+	if !m.filteredValid || filter != m.filteredFor {
+		m.filtered = m.filterChoices(filter)
+		m.filteredFor = filter
+		m.filteredValid = true
+	}
+}
+
+// filteredIndexFunc returns the index of the first choice among the filtered
+// choices for which match returns true.
+func (m *Model[T]) filteredIndexFunc(match func(*Choice[T]) bool) (int, bool) {
+	m.refreshFiltered()
+
+	for idx, choice := range m.filtered {
+		if match(choice) {
+			return idx, true
 		}
+	}
+
+	return 0, false
+}
 
-		available++
+// filterChoices returns all choices that match the given filter text.
+func (m *Model[T]) filterChoices(filter string) []*Choice[T] {
+	if m.Filter == nil {
+		return m.choices
+	}
 
-		if m.PageSize > 0 && (len(choices) >= m.PageSize || ignored < m.scrollOffset) {
-			ignored++
+	choices := []*Choice[T]{}
 
-			continue
+	for _, choice := range m.choices {
+		if m.Filter(filter, choice) {
+			choices = append(choices, choice)
 		}
+	}
+
+	return choices
+}
+
+// exactMatchIndex returns the index of the first choice among the filtered
+// choices whose string representation exactly matches the filter text.
+func (m *Model[T]) exactMatchIndex() (int, bool) {
+	filter := m.filterInput.Value()
+	if m.Filter == nil || filter == "" {
+		return 0, false
+	}
 
-		choices = append(choices, choice)
+	return m.filteredIndexFunc(func(c *Choice[T]) bool { return c.String == filter })
+}
+
+// moveCursorTo selects the choice with the given index among the filtered
+// choices and scrolls such that it is on the current page.
+func (m *Model[T]) moveCursorTo(idx int) {
+	if m.PageSize > 0 && (idx < m.scrollOffset || idx >= m.scrollOffset+m.PageSize) {
+		m.scrollOffset = min(idx, max(0, m.availableChoices-m.PageSize))
+		m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
 	}
 
-	return choices, available
+	m.currentIdx = idx - m.scrollOffset
+}
+
+// ScrollToMsg scrolls a running selection prompt as described in
+// Model.ScrollTo.
+type ScrollToMsg struct {
+	Offset int
+}
+
+// ScrollByMsg scrolls a running selection prompt as described in
+// Model.ScrollBy.
+type ScrollByMsg struct {
+	Lines int
+}
+
+// ScrollTo scrolls the list without moving the cursor such that the choice at
+// the given offset within the choices that match the filter is the first
+// visible choice. The offset is clamped to the valid range. The cursor stays
+// on the selected choice if it is still visible and is otherwise clamped to
+// the visible choices. ScrollTo has no effect if all choices fit on one page.
+func (m *Model[T]) ScrollTo(offset int) {
+	if m.PageSize <= 0 || m.availableChoices <= m.PageSize {
+		return
+	}
+
+	idx := m.scrollOffset + m.currentIdx
+	m.scrollOffset = min(max(0, offset), m.availableChoices-m.PageSize)
+	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+	m.currentIdx = min(max(0, idx-m.scrollOffset), len(m.currentChoices)-1)
+}
+
+// ScrollBy scrolls the list by the given number of choices like ScrollTo.
+// Negative values scroll up.
+func (m *Model[T]) ScrollBy(lines int) {
+	m.ScrollTo(m.scrollOffset + lines)
 }
 
 func (m *Model[T]) canScrollDown() bool {
@@ -454,6 +1175,20 @@ func (m *Model[T]) cursorUp() {
 	m.currentIdx = max(0, m.currentIdx-1)
 }
 
+// centerCursor adjusts the scroll offset such that the selected choice is in
+// the middle of the page unless it is too close to the beginning or the end of
+// the list.
+func (m *Model[T]) centerCursor() {
+	if m.PageSize <= 0 || m.availableChoices <= m.PageSize {
+		return
+	}
+
+	idx := m.scrollOffset + m.currentIdx
+	m.scrollOffset = min(max(0, idx-m.PageSize/2), m.availableChoices-m.PageSize)
+	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+	m.currentIdx = idx - m.scrollOffset
+}
+
 func (m *Model[T]) scrollDown() {
 	if m.PageSize <= 0 || m.scrollOffset+m.PageSize >= m.availableChoices {
 		return
@@ -496,9 +1231,14 @@ func (m *Model[T]) scrollToTop() {
 	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
 }
 
+// reindexChoices updates the indices of the choices and the color profile for
+// their default styles.
 func (m *Model[T]) reindexChoices() {
+	profile := m.colorProfile()
+
 	for i, choice := range m.choices {
 		choice.idx = i
+		choice.profile = profile
 	}
 }
 
@@ -1,14 +1,19 @@
 package selection_test
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/erikgeiser/promptkit"
 	"github.com/erikgeiser/promptkit/selection"
 	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/reflow/ansi"
 	"github.com/muesli/termenv"
 )
 
@@ -251,6 +256,1155 @@ func TestAbort(t *testing.T) {
 	test.AssertGoldenView(t, m, "abort.golden")
 }
 
+func TestViewList(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown)
+	assertNoError(t, m)
+
+	list := test.StripANSI(m.ViewList())
+
+	if strings.Contains(list, "foo:") || strings.Contains(list, s.FilterPrompt) {
+		t.Errorf("list view contains prompt or filter:\n%s", test.Indent(list))
+	}
+
+	expected := "    a\n  ▸ b\n    c\n"
+	if list != expected {
+		t.Errorf("unexpected list view:\n%s\nexpected:\n%s",
+			test.Indent(list), test.Indent(expected))
+	}
+}
+
+func TestMouse(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("pick a choice:", []string{"a", "b", "c"})
+	s.EnableMouse = true
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	// line 0 is the prompt, line 1 the filter and line 2 the first choice
+	test.Run(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 4, Y: 4})
+	assertNoError(t, m)
+
+	choice := getChoice(t, m)
+	if choice != "c" {
+		t.Errorf("unexpected choice after click: %v, expected c", choice)
+	}
+
+	cmd := test.Update(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 4, Y: 3})
+	if cmd != nil {
+		t.Errorf("single click produced command %v", cmd)
+	}
+
+	cmd = test.Update(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 4, Y: 3})
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Errorf("double click did not produce quit signal")
+	}
+
+	choice = getChoice(t, m)
+	if choice != "b" {
+		t.Errorf("unexpected choice after double click: %v, expected b", choice)
+	}
+}
+
+func TestTooltip(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.TooltipFunc = func(c string) string { return "tooltip for " + c }
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "tooltip.golden")
+
+	view := test.StripANSI(m.View())
+	if !strings.HasSuffix(view, "tooltip for b\n") {
+		t.Errorf("tooltip of selected choice was not rendered:\n%s", test.Indent(view))
+	}
+}
+
+func TestWithQuantities(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.WithQuantities = true
+	s.QuantityBounds = func(c string) (int, int) {
+		if c == "b" {
+			return 1, 2
+		}
+
+		return 0, 5
+	}
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyShiftRight, tea.KeyShiftRight, tea.KeyShiftLeft,
+		tea.KeyDown, tea.KeyShiftRight, tea.KeyShiftRight, tea.KeyShiftRight)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "quantities.golden")
+
+	test.Update(t, m, tea.KeyEnter)
+
+	quantities, err := selection.Quantities(m)
+	if err != nil {
+		t.Fatalf("quantities: %v", err)
+	}
+
+	expected := map[string]int{"a": 1, "b": 2}
+	if !reflect.DeepEqual(quantities, expected) {
+		t.Errorf("expected quantities %v, got %v", expected, quantities)
+	}
+
+	test.AssertGoldenView(t, m, "quantities_confirmed.golden")
+}
+
+func TestQuantitiesOnAbort(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.WithQuantities = true
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown, tea.KeyShiftRight, tea.KeyCtrlC)
+
+	quantities, err := selection.Quantities(m)
+	if !errors.Is(err, promptkit.ErrAborted) {
+		t.Fatalf("expected %v, got %v", promptkit.ErrAborted, err)
+	}
+
+	expected := map[string]int{"b": 1}
+	if !reflect.DeepEqual(quantities, expected) {
+		t.Errorf("expected partial quantities %v, got %v", expected, quantities)
+	}
+}
+
+func TestQuantitiesFilterKeys(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c++"})
+	s.WithQuantities = true
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, test.KeyMsg('+'), tea.KeyShiftRight)
+	assertNoError(t, m)
+
+	if choice := getChoice(t, m); choice != "c++" {
+		t.Errorf("+ was not typed into the filter, selected %q", choice)
+	}
+
+	quantities, err := selection.Quantities(m)
+	if err != nil {
+		t.Fatalf("quantities: %v", err)
+	}
+
+	if !reflect.DeepEqual(quantities, map[string]int{"c++": 1}) {
+		t.Errorf("unexpected quantities with filter: %v", quantities)
+	}
+
+	s.Filter = nil
+	m = selection.NewModel(s)
+
+	test.Run(t, m, test.KeyMsg('+'), test.KeyMsg('+'), test.KeyMsg('-'))
+	assertNoError(t, m)
+
+	quantities, err = selection.Quantities(m)
+	if err != nil {
+		t.Fatalf("quantities: %v", err)
+	}
+
+	if !reflect.DeepEqual(quantities, map[string]int{"a": 1}) {
+		t.Errorf("unexpected quantities without filter: %v", quantities)
+	}
+}
+
+func TestQuantitiesResetOnInit(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.WithQuantities = true
+	s.QuantityBounds = func(string) (int, int) { return 1, 5 }
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyShiftRight, tea.KeyShiftRight)
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	quantities, err := selection.Quantities(m)
+	if err != nil {
+		t.Fatalf("quantities: %v", err)
+	}
+
+	expected := map[string]int{"a": 1, "b": 1, "c": 1}
+	if !reflect.DeepEqual(quantities, expected) {
+		t.Errorf("expected quantities %v after Init, got %v", expected, quantities)
+	}
+}
+
+func TestDefaultChoice(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.DefaultChoice = func(c string) bool { return c == "b" }
+	s.Template = `
+{{- range $choice := .Choices }}
+  {{- print $choice.String }}
+  {{- if IsDefault $choice }} (default){{ end }}
+  {{- "\n" }}
+{{- end }}`
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	expected := "a\nb (default)\nc\n"
+	if m.View() != expected {
+		t.Errorf("expected view %q, got %q", expected, m.View())
+	}
+
+	value, err := m.Value()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if value != "b" {
+		t.Errorf("default choice %q was not selected initially, got %q", "b", value)
+	}
+}
+
+type memoryClipboard struct {
+	text string
+}
+
+func (c *memoryClipboard) WriteAll(text string) error {
+	c.text = text
+
+	return nil
+}
+
+func TestDefaultChoiceWithInitialHeight(t *testing.T) {
+	t.Parallel()
+
+	for _, height := range []int{20, 5} {
+		s := selection.New("foo:", []string{"a", "b", "c", "d", "e", "f"})
+		s.DefaultChoice = func(c string) bool { return c == "e" }
+		s.InitialHeight = height
+		m := selection.NewModel(s)
+
+		test.Run(t, m)
+		assertNoError(t, m)
+
+		value, err := m.Value()
+		if err != nil {
+			t.Fatalf("value: %v", err)
+		}
+
+		if value != "e" {
+			t.Errorf("expected default choice e with height %d, got %q", height, value)
+		}
+	}
+}
+
+func TestMouseRegions(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("pick a or b:", []string{"a", "b", "a very long choice"})
+	s.EnableMouse = true
+	s.MaxLabelWidth = 6
+	s.TooltipFunc = func(string) string { return "b" }
+	s.ColorProfile = termenv.Ascii
+	m := selection.NewModel(s)
+
+	// line 0 is the prompt, line 1 the filter, lines 2 to 4 the choices and
+	// line 5 the tooltip
+	test.Run(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 4, Y: 4})
+	assertNoError(t, m)
+
+	if choice := getChoice(t, m); choice != "a very long choice" {
+		t.Errorf("truncated choice could not be clicked, got %q", choice)
+	}
+
+	for _, y := range []int{0, 5} {
+		test.Update(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 0, Y: y})
+
+		if choice := getChoice(t, m); choice != "a very long choice" {
+			t.Errorf("click on line %d that contains a choice's text selected %q", y, choice)
+		}
+	}
+
+	if strings.Contains(m.View(), "\x1b[820") {
+		t.Errorf("view contains hit-testing markers: %q", m.View())
+	}
+}
+
+func TestDefaultStylesColorProfile(t *testing.T) { //nolint:paralleltest
+	s := selection.New("foo:", []string{"a", "b"})
+	s.ColorProfile = termenv.Ascii
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyEnter)
+	assertNoError(t, m)
+
+	if strings.Contains(m.View(), "\x1b[") {
+		t.Errorf("default final style ignores the ColorProfile: %q", m.View())
+	}
+
+	promptkit.SetColorProfile(termenv.Ascii)
+	defer promptkit.ResetColorProfile()
+
+	s = selection.New("foo:", []string{"a", "b"})
+	s.ColorProfile = termenv.TrueColor
+	m = selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	if strings.Contains(m.View(), "\x1b[") {
+		t.Errorf("default selected style ignores the forced color profile: %q", m.View())
+	}
+}
+
+func TestYank(t *testing.T) {
+	t.Parallel()
+
+	clipboard := &memoryClipboard{}
+
+	s := selection.New("foo:", []string{"a", "b", "y"})
+	s.Clipboard = clipboard
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown, test.KeyMsg('y'))
+	assertNoError(t, m)
+
+	if clipboard.text != "" {
+		t.Errorf("yank key was not passed to the filter, copied %q", clipboard.text)
+	}
+
+	test.Update(t, m, tea.KeyMsg{Type: tea.KeyCtrlY})
+	assertNoError(t, m)
+
+	if clipboard.text != "y" {
+		t.Errorf("expected %q to be copied, got %q", "y", clipboard.text)
+	}
+
+	if !strings.Contains(test.StripANSI(m.View()), promptkit.CurrentStrings().Copied) {
+		t.Errorf("copied indicator is not shown:\n%s", test.Indent(m.View()))
+	}
+}
+
+func TestIcons(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b"})
+	s.Icon = promptkit.DefaultIcons.Question
+	s.ResultIcon = promptkit.DefaultIcons.Success
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "icons.golden")
+
+	test.Update(t, m, tea.KeyEnter)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "icons_result.golden")
+}
+
+func TestWrapWidths(t *testing.T) {
+	t.Parallel()
+
+	for _, width := range []int{20, 40, 80} {
+		width := width
+
+		t.Run(fmt.Sprint(width), func(t *testing.T) {
+			t.Parallel()
+
+			s := selection.New("Which of the files in the current directory should be deleted?",
+				[]string{"a-file-with-a-name-that-is-longer-than-the-terminal.txt", "short.txt"})
+			s.Icon = promptkit.DefaultIcons.Question
+			s.ColorProfile = termenv.TrueColor
+			m := selection.NewModel(s)
+
+			test.Run(t, m, tea.WindowSizeMsg{Width: width, Height: 20})
+			assertNoError(t, m)
+			test.AssertGoldenView(t, m, fmt.Sprintf("wrap_%d.golden", width))
+
+			for _, line := range strings.Split(strings.TrimSuffix(m.View(), "\n"), "\n") {
+				if lineWidth := ansi.PrintableRuneWidth(line); lineWidth > width {
+					t.Errorf("line %q with width %d exceeds terminal width", line, lineWidth)
+				}
+			}
+		})
+	}
+}
+
+func TestHeader(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("service:", []string{
+		"api       running", "worker    stopped", "cron      running", "db        running",
+	})
+	s.Header = "Name      Status"
+	s.Filter = nil
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 40, Height: 6}, tea.KeyDown, tea.KeyDown, tea.KeyDown)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "header.golden")
+
+	lines := strings.Split(test.StripANSI(m.View()), "\n")
+	if len(lines) < 2 || lines[1] != "    "+s.Header {
+		t.Fatalf("header is not rendered aligned below the prompt:\n%s", test.Indent(m.View()))
+	}
+
+	if len(lines) > 6 {
+		t.Errorf("page size does not account for the header:\n%s", test.Indent(m.View()))
+	}
+
+	if getChoice(t, m) != "db        running" {
+		t.Errorf("header is selectable")
+	}
+}
+
+func TestFilterBottom(t *testing.T) {
+	t.Parallel()
+
+	choices := []string{"a1", "a2", "a3", "a4", "a5", "a6", "a7", "a8", "b1", "b2"}
+
+	top := selection.NewModel(selection.New("foo:", choices))
+	top.ColorProfile = termenv.TrueColor
+
+	s := selection.New("foo:", choices)
+	s.FilterPosition = selection.FilterBottom
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	msgs := append([]tea.Msg{tea.WindowSizeMsg{Width: 40, Height: 6}}, test.MsgsFromText("a")...)
+	test.Run(t, top, msgs...)
+	test.Run(t, m, msgs...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "filter_bottom.golden")
+
+	if m.PageSize != top.PageSize {
+		t.Errorf("page size %d differs from %d with the filter at the top", m.PageSize, top.PageSize)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(test.StripANSI(m.View()), "\n"), "\n")
+	if len(lines) > 6 {
+		t.Errorf("view exceeds the terminal height:\n%s", test.Indent(m.View()))
+	}
+
+	if !strings.HasPrefix(lines[len(lines)-1], s.FilterPrompt) {
+		t.Errorf("filter input is not rendered below the choices:\n%s", test.Indent(m.View()))
+	}
+}
+
+func TestMaxLabelWidth(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a very long choice", "short"})
+	s.MaxLabelWidth = 8
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "max_label_width.golden")
+
+	view := test.StripANSI(m.View())
+	if !strings.Contains(view, "a very …\n") || !strings.Contains(view, "short\n") {
+		t.Errorf("choices were not truncated correctly:\n%s", test.Indent(m.View()))
+	}
+
+	test.Update(t, m, tea.KeyEnter)
+
+	value, err := m.Value()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if value != "a very long choice" {
+		t.Errorf("truncated value %q was returned", value)
+	}
+}
+
+func TestScrollTo(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c", "d", "e", "f"})
+	s.PageSize = 3
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown)
+
+	m.ScrollTo(1)
+	assertNoError(t, m)
+
+	if value, _ := m.Value(); value != "b" {
+		t.Errorf("cursor moved to %q although the selected choice is visible", value)
+	}
+
+	test.Update(t, m, selection.ScrollByMsg{Lines: 100})
+
+	if value, _ := m.Value(); value != "d" {
+		t.Errorf("cursor was not clamped to the visible choices, got %q", value)
+	}
+
+	test.AssertGoldenView(t, m, "scroll_to.golden")
+
+	test.Update(t, m, selection.ScrollToMsg{Offset: -5})
+
+	if value, _ := m.Value(); value != "c" {
+		t.Errorf("cursor was not clamped to the visible choices, got %q", value)
+	}
+}
+
+func TestBoxDrawingSeparator(t *testing.T) {
+	t.Parallel()
+
+	const width = 20
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.Filter = nil
+	s.Template = `
+{{- Fill "─" .TerminalWidth }}
+{{ range  $i, $choice := .Choices }}
+  {{- if eq $.SelectedIndex $i }}
+    {{- print "┃ " (Selected $choice) "\n" }}
+  {{- else }}
+    {{- print "│ " (Unselected $choice) "\n" }}
+  {{- end }}
+{{- end }}
+{{- Fill "─" .TerminalWidth }}`
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: width, Height: 10}, tea.KeyDown)
+	assertNoError(t, m)
+
+	lines := strings.Split(strings.TrimSuffix(test.StripANSI(m.View()), "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("unexpected number of lines:\n%s", test.Indent(strings.Join(lines, "\n")))
+	}
+
+	for _, idx := range []int{0, len(lines) - 1} {
+		if lines[idx] != strings.Repeat("─", width) {
+			t.Errorf("separator %q does not span the terminal width %d", lines[idx], width)
+		}
+	}
+
+	if lines[2] != "┃ b" {
+		t.Errorf("unexpected selected row %q", lines[2])
+	}
+}
+
+func TestSelectSoleFilteredChoice(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"apple", "banana", "cherry"})
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, append(test.MsgsFromText("nan"), tea.KeyEnter)...)
+	assertNoError(t, m)
+
+	choice := getChoice(t, m)
+	if choice != "banana" {
+		t.Errorf("unexpected choice: %v, expected banana", choice)
+	}
+}
+
+func TestSelectHighlightedChoice(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"apple", "banana", "cherry"})
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, append(test.MsgsFromText("e"), tea.KeyDown, tea.KeyEnter)...)
+	assertNoError(t, m)
+
+	choice := getChoice(t, m)
+	if choice != "cherry" {
+		t.Errorf("unexpected choice: %v, expected cherry", choice)
+	}
+}
+
+func TestSelectWithoutFilteredChoices(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"apple", "banana", "cherry"})
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, append(test.MsgsFromText("xyz"), tea.KeyEnter)...)
+	assertNoError(t, m)
+
+	_, err := m.Value()
+	if err == nil {
+		t.Errorf("select without matching choices produced a value")
+	}
+
+	if !strings.Contains(test.StripANSI(m.View()), "xyz") {
+		t.Errorf("prompt concluded without matching choices:\n%s", test.Indent(m.View()))
+	}
+}
+
+func TestShowMatchCount(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"apple", "banana", "cherry"})
+	s.ShowMatchCount = true
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	if strings.Contains(test.StripANSI(m.View()), "→") {
+		t.Errorf("match count rendered without filter:\n%s", test.Indent(m.View()))
+	}
+
+	if !strings.Contains(test.StripANSI(m.View()), "3 items") {
+		t.Errorf("total count not rendered without filter:\n%s", test.Indent(m.View()))
+	}
+
+	test.Update(t, m, test.KeyMsg('e'))
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "show_match_count.golden")
+
+	if !strings.Contains(test.StripANSI(m.View()), "3 → 2") {
+		t.Errorf("match count not rendered:\n%s", test.Indent(m.View()))
+	}
+}
+
+func TestInitialCounts(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"apple", "banana", "cherry"})
+	s.Template = `{{ .FilteredCount }} of {{ .TotalCount }}`
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	if view := m.View(); view != "3 of 3" {
+		t.Errorf("unexpected initial counts %q", view)
+	}
+
+	test.Update(t, m, test.KeyMsg('e'))
+
+	if view := m.View(); view != "2 of 3" {
+		t.Errorf("unexpected filtered counts %q", view)
+	}
+}
+
+func TestExtendedTemplateFuncsOverrideUtilFuncs(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"apple", "banana", "cherry"})
+	s.Template = `{{ Truncate .Prompt 1 }}`
+	s.ExtendedTemplateFuncs["Truncate"] = func(s string, _ int) string { return "custom " + s }
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	if view := m.View(); view != "custom foo:" {
+		t.Errorf("extended template function did not take precedence: %q", view)
+	}
+}
+
+func TestFilterDebounce(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"apple", "banana", "cherry"})
+	s.FilterDebounce = time.Millisecond
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+
+	first := test.Update(t, m, test.KeyMsg('c'))
+	last := test.Update(t, m, test.KeyMsg('h'))
+	assertNoError(t, m)
+
+	if !strings.Contains(test.StripANSI(m.View()), "apple") {
+		t.Errorf("choices were filtered before the debounce delay:\n%s", test.Indent(m.View()))
+	}
+
+	test.Update(t, m, debounceMsg(t, first))
+
+	if !strings.Contains(test.StripANSI(m.View()), "apple") {
+		t.Errorf("outdated debounce tick filtered the choices:\n%s", test.Indent(m.View()))
+	}
+
+	test.Update(t, m, debounceMsg(t, last))
+
+	if strings.Contains(test.StripANSI(m.View()), "apple") {
+		t.Errorf("choices were not filtered after the debounce delay:\n%s", test.Indent(m.View()))
+	}
+}
+
+func TestFilterDebounceSelect(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"apple", "banana", "cherry"})
+	s.FilterDebounce = time.Hour
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, append(test.MsgsFromText("ch"), tea.KeyEnter)...)
+	assertNoError(t, m)
+
+	choice := getChoice(t, m)
+	if choice != "cherry" {
+		t.Errorf("unexpected choice: %v, expected cherry", choice)
+	}
+}
+
+// debounceMsg returns the message of the debounce tick that is the last
+// command of the batch returned by the filter update.
+func debounceMsg(tb testing.TB, cmd tea.Cmd) tea.Msg {
+	tb.Helper()
+
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		tb.Fatalf("filter update did not return a batch of commands")
+	}
+
+	return batch[len(batch)-1]()
+}
+
+func TestStyleFunc(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"installed", "deprecated", "other"})
+	s.StyleFunc = func(value string, state selection.RowState) string {
+		switch {
+		case value == "installed":
+			return termenv.String(state.Rendered).Foreground(termenv.ANSIGreen).String()
+		case value == "deprecated" && !state.Highlighted:
+			return termenv.String(state.Rendered).CrossOut().String()
+		default:
+			return state.Rendered
+		}
+	}
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown, tea.KeyDown)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "style_func.golden")
+}
+
+func TestRecordAndReplayKeys(t *testing.T) {
+	t.Parallel()
+
+	var keys []tea.KeyMsg
+
+	s := selection.New("foo:", []string{"apple", "banana", "cherry"})
+	s.RecordKeys = &keys
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, append(test.MsgsFromText("a"), tea.KeyDown, tea.KeyDown, tea.KeyEnter)...)
+	assertNoError(t, m)
+
+	if len(keys) != 4 {
+		t.Fatalf("recorded %d instead of 4 keys", len(keys))
+	}
+
+	replaySelection := selection.New("foo:", []string{"apple", "banana", "cherry"})
+	replaySelection.ColorProfile = termenv.TrueColor
+	replayModel := selection.NewModel(replaySelection)
+	replayModel.Init()
+
+	promptkit.ReplayKeys(replayModel, keys)
+	assertNoError(t, replayModel)
+
+	if getChoice(t, replayModel) != getChoice(t, m) {
+		t.Errorf("replay produced %v instead of %v", getChoice(t, replayModel), getChoice(t, m))
+	}
+}
+
+func TestChoicesMsgKeepsSelectionByIdentity(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.Identity = func(c string) string { return c }
+	s.WithQuantities = true
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown, tea.KeyShiftRight)
+	test.Update(t, m, selection.ChoicesMsg[string]{Choices: []string{"x", "y", "b"}})
+	assertNoError(t, m)
+
+	choice := getChoice(t, m)
+	if choice != "b" {
+		t.Errorf("unexpected choice after refresh: %v, expected b", choice)
+	}
+
+	quantities, err := selection.Quantities(m)
+	if err != nil {
+		t.Fatalf("quantities: %v", err)
+	}
+
+	if !reflect.DeepEqual(quantities, map[string]int{"b": 1}) {
+		t.Errorf("unexpected quantities after refresh: %v", quantities)
+	}
+}
+
+func TestChoicesMsgKeepsSelectionByIndex(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown)
+	test.Update(t, m, selection.ChoicesMsg[string]{Choices: []string{"x", "y", "z"}})
+	assertNoError(t, m)
+
+	choice := getChoice(t, m)
+	if choice != "y" {
+		t.Errorf("unexpected choice after refresh: %v, expected y", choice)
+	}
+}
+
+func TestChoicesMsgGrowsPageSize(t *testing.T) {
+	t.Parallel()
+
+	many := make([]string, 100)
+	for i := range many {
+		many[i] = fmt.Sprintf("choice%d", i)
+	}
+
+	s := selection.New("foo:", []string{"a", "b"})
+	s.PageSize = 5
+	s.ColorProfile = termenv.Ascii
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	test.Update(t, m, tea.WindowSizeMsg{Width: 80, Height: 20})
+	test.Update(t, m, selection.ChoicesMsg[string]{Choices: many})
+	assertNoError(t, m)
+
+	if m.PageSize != 5 {
+		t.Errorf("page size is %d after the choices grew, expected 5", m.PageSize)
+	}
+
+	s = selection.New("foo:", []string{"a"})
+	s.ColorProfile = termenv.Ascii
+	m = selection.NewModel(s)
+
+	test.Run(t, m)
+	test.Update(t, m, selection.ChoicesMsg[string]{Choices: []string{}})
+	test.Update(t, m, tea.WindowSizeMsg{Width: 80, Height: 10})
+	test.Update(t, m, selection.ChoicesMsg[string]{Choices: many})
+	assertNoError(t, m)
+
+	if height := promptkit.MeasureHeight(m.View(), 80); m.PageSize <= 0 || height >= 10 {
+		t.Errorf("view with page size %d and height %d overflows the terminal", m.PageSize, height)
+	}
+}
+
+func TestInitialSize(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c", "d", "e", "f", "g", "h"})
+	s.InitialWidth = 20
+	s.InitialHeight = 6
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	height := promptkit.MeasureHeight(m.View(), 20)
+	if height >= 6 {
+		t.Errorf("view with %d lines does not fit the initial height:\n%s", height, test.Indent(m.View()))
+	}
+}
+
+func TestManaged(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.Managed = true
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+
+	cmd := test.Update(t, m, tea.WindowSizeMsg{Width: 40, Height: 10})
+	if cmd != nil {
+		t.Errorf("managed model returned a command on resize")
+	}
+
+	cmd = test.Update(t, m, tea.KeyEnter)
+	if cmd == nil {
+		t.Fatalf("managed model did not report its conclusion")
+	}
+
+	done, ok := cmd().(selection.DoneMsg[string])
+	if !ok || done.Value != "a" || done.Err != nil {
+		t.Errorf("unexpected conclusion %#v", done)
+	}
+
+	if cmd := test.Update(t, m, tea.KeyDown); cmd != nil {
+		t.Errorf("concluded model still updates")
+	}
+
+	value, err := m.Value()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if value != "a" {
+		t.Errorf("unexpected value %q", value)
+	}
+}
+
+func TestEmptyEnterAborts(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.EmptyEnterAborts = true
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyEnter)
+
+	if !errors.Is(m.Err, promptkit.ErrAborted) {
+		t.Fatalf("enter on empty filter produced %v instead of %v", m.Err, promptkit.ErrAborted)
+	}
+
+	m = selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown, tea.KeyUp, tea.KeyEnter)
+	assertNoError(t, m)
+
+	choice := getChoice(t, m)
+	if choice != "a" {
+		t.Errorf("unexpected choice: %v, expected a", choice)
+	}
+}
+
+func TestExactMatchConfirm(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"abc", "ab", "a"})
+	s.ExactMatchConfirm = true
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, test.MsgsFromText("ab")...)
+	assertNoError(t, m)
+
+	choice := getChoice(t, m)
+	if choice != "abc" {
+		t.Fatalf("unexpected choice before confirmation: %v, expected abc", choice)
+	}
+
+	test.Update(t, m, tea.KeyEnter)
+
+	choice = getChoice(t, m)
+	if choice != "ab" {
+		t.Errorf("unexpected choice after confirmation: %v, expected ab", choice)
+	}
+}
+
+func TestTrimBlankLines(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b"})
+	s.Template = "\n\n{{ range .Choices }}{{ .String }}\n{{ end }}\n\n"
+	s.ResultTemplate = "\n{{ .FinalChoice.String }}\n\n"
+	s.TrimBlankLines = true
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	if view := m.View(); view != "a\nb\n" {
+		t.Errorf("unexpected trimmed view: %q", view)
+	}
+
+	test.Update(t, m, tea.KeyEnter)
+
+	if view := m.View(); view != "a\n" {
+		t.Errorf("unexpected trimmed result view: %q", view)
+	}
+}
+
+func TestResultDisplayFunc(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("region:", []string{"eu-west-1", "us-east-1"})
+	s.ResultDisplayFunc = func(value string) string {
+		return map[string]string{"eu-west-1": "Ireland", "us-east-1": "Virginia"}[value]
+	}
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown, tea.KeyEnter)
+	assertNoError(t, m)
+
+	if view := test.StripANSI(m.View()); view != "region: Virginia\n" {
+		t.Errorf("unexpected result view: %q", view)
+	}
+
+	if choice := getChoice(t, m); choice != "us-east-1" {
+		t.Errorf("unexpected choice %q, expected us-east-1", choice)
+	}
+}
+
+func TestResultContextLines(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c", "d", "e"})
+	s.ResultContextLines = 1
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown, tea.KeyDown, tea.KeyEnter)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "result_context_lines.golden")
+
+	expected := "foo:\n  b\n▸ c\n  d\n"
+	if view := test.StripANSI(m.View()); view != expected {
+		t.Errorf("unexpected result view:\n%s\nexpected:\n%s",
+			test.Indent(view), test.Indent(expected))
+	}
+}
+
+func TestCenterCursor(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c", "d", "e", "f", "g"})
+	s.PageSize = 3
+	s.CenterCursor = true
+	s.Filter = nil
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown)
+	assertNoError(t, m)
+
+	expectPage := func(expected string) {
+		t.Helper()
+
+		view := test.StripANSI(m.View())
+		if !strings.HasSuffix(view, expected) {
+			t.Errorf("unexpected page:\n%s\nexpected:\n%s", test.Indent(view), test.Indent(expected))
+		}
+	}
+
+	expectPage("    a\n  ▸ b\n⇣   c\n")
+
+	test.Update(t, m, tea.KeyDown)
+	expectPage("⇡   b\n  ▸ c\n⇣   d\n")
+
+	for i := 0; i < 4; i++ {
+		test.Update(t, m, tea.KeyDown)
+	}
+
+	expectPage("⇡   e\n    f\n  ▸ g\n")
+
+	test.Update(t, m, tea.KeyUp)
+	test.Update(t, m, tea.KeyUp)
+	expectPage("⇡   d\n  ▸ e\n⇣   f\n")
+}
+
+func TestInterrupt(t *testing.T) {
+	t.Parallel()
+
+	newModel := func() *selection.Model[string] {
+		s := selection.New("foo:", []string{"a", "b", "c"})
+		s.KeyMap.Abort = []string{"esc"}
+		s.KeyMap.Interrupt = []string{"ctrl+c"}
+
+		return selection.NewModel(s)
+	}
+
+	m := newModel()
+	test.Run(t, m, tea.KeyEsc)
+
+	if !errors.Is(m.Err, promptkit.ErrAborted) {
+		t.Errorf("abort key produced %v instead of %q", m.Err, promptkit.ErrAborted)
+	}
+
+	m = newModel()
+	test.Run(t, m, tea.KeyCtrlC)
+
+	if !errors.Is(m.Err, promptkit.ErrInterrupted) {
+		t.Errorf("interrupt key produced %v instead of %q", m.Err, promptkit.ErrInterrupted)
+	}
+}
+
+func TestFilterPromptAndPlaceholder(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.FilterPrompt = "Search ›"
+	s.FilterPlaceholder = "type here"
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	view := test.StripANSI(m.View())
+	if !strings.Contains(view, "Search › type here") {
+		t.Errorf("custom filter prompt or placeholder was not rendered:\n%s", test.Indent(view))
+	}
+
+	s.FilterPlaceholder = ""
+	m = selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	view = test.StripANSI(m.View())
+	if !strings.Contains(view, selection.DefaultFilterPlaceholder) {
+		t.Errorf("default filter placeholder was not rendered:\n%s", test.Indent(view))
+	}
+}
+
+func TestBack(t *testing.T) {
+	t.Parallel()
+
+	m := selection.NewModel(selection.New("foo:", []string{
+		"a", "b", "c",
+	}))
+	m.EnableBack = true
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, test.KeyMsg('a'), tea.KeyEsc)
+	assertNoError(t, m)
+
+	test.Update(t, m, tea.KeyEsc)
+
+	if !errors.Is(m.Err, selection.ErrBack) {
+		t.Fatalf("going back produced %v instead of %q", m.Err, selection.ErrBack)
+	}
+}
+
 func TestSubmit(t *testing.T) {
 	t.Parallel()
 
@@ -408,3 +1562,355 @@ func assertNoError[T any](tb testing.TB, m *selection.Model[T]) {
 		tb.Fatalf("model contains error: %v", m.Err)
 	}
 }
+
+func BenchmarkNavigateLargeList(b *testing.B) {
+	choices := make([]int, 100000)
+	for i := range choices {
+		choices[i] = i
+	}
+
+	s := selection.New("foo:", choices)
+	s.PageSize = 10
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewModel(s)
+
+	test.Run(b, m, test.MsgsFromText("1")...)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		test.Update(b, m, tea.KeyDown)
+		_ = m.View()
+	}
+}
+
+func TestForwardUnhandledKeys(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b"})
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+
+	cmd := test.Update(t, m, tea.KeyF1)
+	if cmd != nil {
+		t.Fatalf("unhandled key was forwarded without ForwardUnhandledKeys")
+	}
+
+	s.ForwardUnhandledKeys = true
+
+	cmd = test.Update(t, m, tea.KeyF1)
+	if cmd == nil {
+		t.Fatalf("unhandled key was not forwarded")
+	}
+
+	expected := promptkit.UnhandledKeyMsg{Key: tea.KeyMsg{Type: tea.KeyF1}}
+	if msg := cmd(); !reflect.DeepEqual(msg, expected) {
+		t.Errorf("unexpected message %#v, expected %#v", msg, expected)
+	}
+
+	cmd = test.Update(t, m, test.KeyMsg('a'))
+	if cmd != nil {
+		if _, ok := cmd().(promptkit.UnhandledKeyMsg); ok {
+			t.Errorf("handled key was forwarded")
+		}
+	}
+}
+
+func TestThenConfirmAborted(t *testing.T) {
+	t.Parallel()
+
+	output := &bytes.Buffer{}
+
+	s := selection.New("foo:", []string{"a", "b"})
+	s.ColorProfile = termenv.Ascii
+	s.Input = strings.NewReader("\x03")
+	s.Output = output
+
+	value, confirmed, err := s.ThenConfirm("Use it?")
+	if !errors.Is(err, promptkit.ErrAborted) {
+		t.Fatalf("unexpected error %v, expected %v", err, promptkit.ErrAborted)
+	}
+
+	if value != "" || confirmed {
+		t.Errorf("unexpected result %q, %v for aborted prompt", value, confirmed)
+	}
+
+	if strings.Contains(output.String(), "Use") {
+		t.Errorf("confirmation was shown after aborted selection: %q", output.String())
+	}
+}
+
+func TestShowRecent(t *testing.T) {
+	t.Parallel()
+
+	store := selection.NewMemoryRecentStore()
+
+	for _, item := range []string{"c", "d", "b"} {
+		err := store.Add(item)
+		if err != nil {
+			t.Fatalf("add recent item: %v", err)
+		}
+	}
+
+	s := selection.New("foo:", []string{"a", "b", "c", "d"})
+	s.ColorProfile = termenv.Ascii
+	s.RecentStore = store
+	s.ShowRecent = 2
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	lines := strings.Split(test.StripANSI(m.View()), "\n")
+
+	expected := []string{
+		"foo:", "Filter: Type to filter choices", "  ▸ b (recent)", "    d (recent)", "    a", "    c",
+	}
+	if !reflect.DeepEqual(lines[:len(expected)], expected) {
+		t.Fatalf("unexpected choices %q, expected %q", lines[:len(expected)], expected)
+	}
+
+	test.Update(t, m, tea.KeyDown)
+	test.Update(t, m, tea.KeyDown)
+	test.Update(t, m, tea.KeyEnter)
+	assertNoError(t, m)
+
+	if value, _ := m.Value(); value != "a" {
+		t.Fatalf("unexpected value %q, expected a", value)
+	}
+
+	recent, err := store.Get()
+	if err != nil {
+		t.Fatalf("get recent items: %v", err)
+	}
+
+	if !reflect.DeepEqual(recent, []string{"a", "b", "d", "c"}) {
+		t.Errorf("unexpected recent items %q", recent)
+	}
+}
+
+func TestShowRecentDoubleClick(t *testing.T) {
+	t.Parallel()
+
+	store := selection.NewMemoryRecentStore()
+
+	err := store.Add("c")
+	if err != nil {
+		t.Fatalf("add recent item: %v", err)
+	}
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.ColorProfile = termenv.Ascii
+	s.EnableMouse = true
+	s.RecentStore = store
+	s.ShowRecent = 1
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	list := strings.Split(test.StripANSI(m.ViewList()), "\n")
+	if list[0] != "  ▸ c (recent)" {
+		t.Fatalf("recent choice is not marked in the list: %q", list)
+	}
+
+	// line 0 is the prompt, line 1 the filter and line 2 the first choice
+	test.Update(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 4, Y: 4})
+
+	cmd := test.Update(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 4, Y: 4})
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Fatalf("double click did not produce quit signal")
+	}
+
+	assertNoError(t, m)
+
+	recent, err := store.Get()
+	if err != nil {
+		t.Fatalf("get recent items: %v", err)
+	}
+
+	if !reflect.DeepEqual(recent, []string{"b", "c"}) {
+		t.Errorf("unexpected recent items %q", recent)
+	}
+}
+
+func TestShowRecentIdentity(t *testing.T) {
+	t.Parallel()
+
+	store := selection.NewMemoryRecentStore()
+
+	err := store.Add("id-c")
+	if err != nil {
+		t.Fatalf("add recent item: %v", err)
+	}
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.Identity = func(c string) string { return "id-" + c }
+	s.ColorProfile = termenv.Ascii
+	s.RecentStore = store
+	s.ShowRecent = 1
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	test.Update(t, m, selection.ChoicesMsg[string]{Choices: []string{"x", "c", "y"}})
+	assertNoError(t, m)
+
+	lines := strings.Split(test.StripANSI(m.View()), "\n")
+
+	expected := []string{"foo:", "Filter: Type to filter choices", "  ▸ c (recent)", "    x", "    y"}
+	if !reflect.DeepEqual(lines[:len(expected)], expected) {
+		t.Fatalf("unexpected choices %q, expected %q", lines[:len(expected)], expected)
+	}
+
+	test.Update(t, m, tea.KeyDown)
+	test.Update(t, m, tea.KeyEnter)
+	assertNoError(t, m)
+
+	recent, err := store.Get()
+	if err != nil {
+		t.Fatalf("get recent items: %v", err)
+	}
+
+	if !reflect.DeepEqual(recent, []string{"id-x", "id-c"}) {
+		t.Errorf("unexpected recent items %q", recent)
+	}
+}
+
+func TestMemoryRecentStoreLimit(t *testing.T) {
+	t.Parallel()
+
+	store := selection.NewMemoryRecentStore()
+	store.Limit = 2
+
+	for _, item := range []string{"a", "b", "c", "b"} {
+		err := store.Add(item)
+		if err != nil {
+			t.Fatalf("add recent item: %v", err)
+		}
+	}
+
+	recent, err := store.Get()
+	if err != nil {
+		t.Fatalf("get recent items: %v", err)
+	}
+
+	if !reflect.DeepEqual(recent, []string{"b", "c"}) {
+		t.Errorf("unexpected recent items %q", recent)
+	}
+}
+
+func TestProgramOptions(t *testing.T) {
+	t.Parallel()
+
+	output := &bytes.Buffer{}
+	override := &bytes.Buffer{}
+	applied := false
+
+	s := selection.New("foo:", []string{"a", "b"})
+	s.ColorProfile = termenv.Ascii
+	s.Input = strings.NewReader("\r")
+	s.Output = output
+	s.ProgramOptions = []tea.ProgramOption{
+		tea.WithOutput(override),
+		func(*tea.Program) { applied = true },
+	}
+
+	_, err := s.RunPrompt()
+	if err != nil {
+		t.Fatalf("run prompt: %v", err)
+	}
+
+	if !applied {
+		t.Errorf("program option was not applied")
+	}
+
+	if output.Len() != 0 {
+		t.Errorf("program option did not override the output: %q", output.String())
+	}
+
+	if !strings.Contains(override.String(), "foo:") {
+		t.Errorf("selection was not rendered to the overridden output: %q",
+			override.String())
+	}
+}
+
+func TestAltScreenLineEnding(t *testing.T) {
+	t.Parallel()
+
+	output := &bytes.Buffer{}
+
+	s := selection.New("foo:", []string{"a", "b"})
+	s.ColorProfile = termenv.Ascii
+	s.AltScreen = true
+	s.LineEnding = promptkit.CRLF
+	s.Input = strings.NewReader("\r")
+	s.Output = output
+
+	_, err := s.RunPrompt()
+	if err != nil {
+		t.Fatalf("run prompt: %v", err)
+	}
+
+	if !strings.HasSuffix(output.String(), "foo: a\r\n") {
+		t.Fatalf("result after leaving the alt screen does not end with CRLF: %q",
+			output.String())
+	}
+
+	if strings.Count(output.String(), "\n") != strings.Count(output.String(), "\r\n") {
+		t.Errorf("output contains bare line feeds: %q", output.String())
+	}
+}
+
+func TestMouseInlinePrompt(t *testing.T) {
+	t.Parallel()
+
+	output := &bytes.Buffer{}
+
+	// two left clicks on the absolute screen row 3 (X10 mouse encoding), which
+	// is the second choice because the mouse enables the alternate screen in
+	// which the view starts at row 0
+	click := "\x1b[M" + string(rune(32)) + string(rune(32+1+4)) + string(rune(32+1+3))
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.ColorProfile = termenv.Ascii
+	s.EnableMouse = true
+	s.Input = strings.NewReader(click + click)
+	s.Output = output
+
+	choice, err := s.RunPrompt()
+	if err != nil {
+		t.Fatalf("run prompt: %v", err)
+	}
+
+	if choice != "b" {
+		t.Errorf("unexpected choice %q, expected b", choice)
+	}
+
+	if !strings.Contains(output.String(), "\x1b[?1049h") {
+		t.Errorf("inline prompt with mouse support was not rendered in the alternate screen")
+	}
+}
+
+func TestRunPromptWithQuantities(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b"})
+	s.ColorProfile = termenv.Ascii
+	s.Input = strings.NewReader("\r")
+	s.Output = &bytes.Buffer{}
+
+	quantities, err := selection.RunPromptWithQuantities(s)
+	if err != nil {
+		t.Fatalf("run prompt: %v", err)
+	}
+
+	if len(quantities) != 0 {
+		t.Errorf("unexpected quantities %v", quantities)
+	}
+
+	if s.WithQuantities {
+		t.Errorf("running the prompt with quantities modified the selection")
+	}
+}
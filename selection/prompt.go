@@ -11,6 +11,7 @@ import (
 	"os"
 	"strings"
 	"text/template"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -23,12 +24,15 @@ const (
 	// be copied as a starting point for a custom template.
 	DefaultTemplate = `
 {{- if .Prompt -}}
-  {{ Bold .Prompt }}
+  {{ if .Icon }}{{ print .Icon " " }}{{ end }}{{ Bold .Prompt }}
 {{ end -}}
-{{ if .IsFiltered }}
+{{ if and .IsFiltered (not .FilterAtBottom) }}
   {{- print .FilterPrompt " " .FilterInput }}
 {{ end }}
 
+{{- if .Header }}
+  {{- print "    " (Bold .Header) "\n" }}
+{{- end }}
 {{- range  $i, $choice := .Choices }}
   {{- if IsScrollUpHintPosition $i }}
     {{- "⇡ " -}}
@@ -39,37 +43,161 @@ const (
   {{- end -}}
 
   {{- if eq $.SelectedIndex $i }}
-   {{- print (Foreground "32" (Bold "▸ ")) (Selected $choice) "\n" }}
+   {{- print (ThemeSelected (Bold "▸ ")) (Selected $choice) }}
   {{- else }}
-    {{- print "  " (Unselected $choice) "\n" }}
+    {{- print "  " (Unselected $choice) }}
   {{- end }}
+
+  {{- if $choice.Recent }}
+    {{- print " " (Faint (Strings).Recent) }}
+  {{- end }}
+
+  {{- if $.WithQuantities }}
+    {{- print " " (Faint (print "× " $choice.Quantity)) }}
+  {{- end }}
+  {{- "\n" }}
+{{- end}}
+{{- if and .IsFiltered .FilterAtBottom }}
+  {{- print .FilterPrompt " " .FilterInput "\n" }}
+{{- end }}
+{{- if .ShowMatchCount }}
+  {{- if not .IsNarrowed }}
+    {{- print (ThemeHelp (printf (Strings).Items .TotalCount)) "\n" }}
+  {{- else if .Narrowing }}
+    {{- print (ThemeHelp (print (Strings).Narrowing " " .TotalCount " → " .FilteredCount)) "\n" }}
+  {{- else }}
+    {{- print (ThemeHelp (print .TotalCount " → " .FilteredCount)) "\n" }}
+  {{- end }}
+{{- end }}
+{{- if .Tooltip }}
+  {{- print (ThemeHelp .Tooltip) "\n" }}
+{{- end }}
+{{- if .Copied }}
+  {{- print (ThemeHelp (Strings).Copied) "\n" }}
+{{- end }}`
+
+	// DefaultListTemplate defines the default appearance of the list of
+	// choices without the prompt and the filter as rendered by Model.ViewList.
+	DefaultListTemplate = `
+{{- if .Header }}
+  {{- print "    " (Bold .Header) "\n" }}
+{{- end }}
+{{- range  $i, $choice := .Choices }}
+  {{- if IsScrollUpHintPosition $i }}
+    {{- "⇡ " -}}
+  {{- else if IsScrollDownHintPosition $i -}}
+    {{- "⇣ " -}}
+  {{- else -}}
+    {{- "  " -}}
+  {{- end -}}
+
+  {{- if eq $.SelectedIndex $i }}
+   {{- print (ThemeSelected (Bold "▸ ")) (Selected $choice) }}
+  {{- else }}
+    {{- print "  " (Unselected $choice) }}
+  {{- end }}
+
+  {{- if $choice.Recent }}
+    {{- print " " (Faint (Strings).Recent) }}
+  {{- end }}
+
+  {{- if $.WithQuantities }}
+    {{- print " " (Faint (print "× " $choice.Quantity)) }}
+  {{- end }}
+  {{- "\n" }}
 {{- end}}`
 
 	// DefaultResultTemplate defines the default appearance with which the
 	// finale result of the selection is presented.
 	DefaultResultTemplate = `
-	{{- print .Prompt " " (Final .FinalChoice) "\n" -}}
+	{{- if .ResultIcon }}{{ print .ResultIcon " " }}{{ end -}}
+	{{- if .ContextChoices -}}
+		{{- print .Prompt "\n" -}}
+		{{- range $i, $choice := .ContextChoices }}
+			{{- if eq $.ContextSelectedIndex $i }}
+				{{- print (ThemeSelected (Bold "▸ ")) (Final $choice) "\n" }}
+			{{- else }}
+				{{- print "  " $choice.String "\n" }}
+			{{- end }}
+		{{- end }}
+	{{- else if .WithQuantities -}}
+		{{- print .Prompt " " -}}
+		{{- range $i, $choice := .QuantityChoices }}
+			{{- if $i }}{{ ", " }}{{ end }}
+			{{- print (Final $choice) " × " $choice.Quantity }}
+		{{- end }}
+		{{- "\n" -}}
+	{{- else -}}
+		{{- print .Prompt " " (Final .FinalChoice) "\n" -}}
+	{{- end -}}
 	`
 
 	// DefaultFilterPrompt is the default prompt for the filter input when
-	// filtering is enabled.
+	// filtering is enabled unless it is localized with promptkit.SetStrings.
 	DefaultFilterPrompt = "Filter:"
 
 	// DefaultFilterPlaceholder is printed by default when no filter text was
-	// entered yet.
+	// entered yet unless it is localized with promptkit.SetStrings.
 	DefaultFilterPlaceholder = "Type to filter choices"
 
 	accentColor = termenv.ANSI256Color(32)
 )
 
-// DefaultSelectedChoiceStyle is the default style for selected choices.
+// defaultTheme holds the colors of the built-in templates which are used for
+// the empty colors of the Theme.
+var defaultTheme = promptkit.Theme{SelectedColor: "32"}
+
+// Templates holds all built-in templates by name such that they can be
+// selected by a string, for example from a configuration file. The matching
+// result templates are stored under the same name in ResultTemplates.
+var Templates = map[string]string{
+	"default": DefaultTemplate,
+}
+
+// ResultTemplates holds all built-in result templates by name. The names
+// correspond to the templates in Templates.
+var ResultTemplates = map[string]string{
+	"default": DefaultResultTemplate,
+}
+
+// ErrBack is returned when the user pressed a Back key while EnableBack is
+// set. It signals that the user wants to return to a previous prompt, in
+// contrast to promptkit.ErrAborted which signals that the user wants to cancel
+// entirely.
+var ErrBack = fmt.Errorf("back")
+
+// FilterPosition determines where the filter input is rendered.
+type FilterPosition int
+
+const (
+	// FilterTop renders the filter input above the choices.
+	FilterTop FilterPosition = iota
+	// FilterBottom renders the filter input below the choices.
+	FilterBottom
+)
+
+// DefaultSelectedChoiceStyle is the default style for selected choices. It is
+// rendered with the color profile of the selection that the choice belongs to.
 func DefaultSelectedChoiceStyle[T any](c *Choice[T]) string {
-	return termenv.String(c.String).Foreground(accentColor).Bold().String()
+	return c.profile.String(c.String).Foreground(c.profile.Convert(accentColor)).Bold().String()
 }
 
-// DefaultFinalChoiceStyle is the default style for final choices.
+// DefaultFinalChoiceStyle is the default style for final choices. It is
+// rendered with the color profile of the selection that the choice belongs to.
 func DefaultFinalChoiceStyle[T any](c *Choice[T]) string {
-	return termenv.String(c.String).Foreground(accentColor).String()
+	return c.profile.String(c.String).Foreground(c.profile.Convert(accentColor)).String()
+}
+
+// RowState describes the state of a choice that is passed to StyleFunc.
+type RowState struct {
+	// Highlighted is true if the choice is currently selected by the cursor.
+	Highlighted bool
+	// Checked is true if the choice has a non-zero quantity while
+	// WithQuantities is enabled.
+	Checked bool
+	// Rendered is the choice as rendered by the SelectedChoiceStyle or the
+	// UnselectedChoiceStyle.
+	Rendered string
 }
 
 // Selection represents a configurable selection prompt.
@@ -83,19 +211,44 @@ type Selection[T any] struct {
 	// the choices.
 	Prompt string
 
-	// FilterPrompt is the prompt for the filter if filtering is enabled.
+	// Icon is rendered in front of the prompt by the built-in templates, for
+	// example promptkit.DefaultIcons.Question. ResultIcon is rendered in front
+	// of the prompt by the built-in result templates, for example
+	// promptkit.DefaultIcons.Success. By default, no icons are rendered.
+	Icon       string
+	ResultIcon string
+
+	// FilterPrompt is the prompt for the filter if filtering is enabled. It
+	// is rendered in front of the filter input by the default template and is
+	// available as the FilterPrompt template variable. By default,
+	// DefaultFilterPrompt is used.
 	FilterPrompt string
 
+	// FilterPosition determines whether the default template renders the
+	// filter input above the choices (FilterTop) or below them (FilterBottom)
+	// like fzf. It is available as the FilterAtBottom template variable. By
+	// default, the filter input is rendered at the top.
+	FilterPosition FilterPosition
+
 	// Filter is a function that decides whether a given choice should be
 	// displayed based on the text entered by the user into the filter input
 	// field. If Filter is nil, filtering will be disabled. By default the
 	// filter FilterContainsCaseInsensitive is used.
 	Filter func(filterText string, choice *Choice[T]) bool
 
+	// FilterDebounce delays filtering until the user stopped typing for the
+	// given duration, which keeps the filter input responsive when the Filter
+	// is expensive or the list is very large. The choices are always filtered
+	// with the final filter text, at the latest when a Select key is pressed.
+	// By default, the choices are filtered on every keystroke.
+	FilterDebounce time.Duration
+
 	// FilterPlaceholder holds the text that is displayed in the filter input
 	// field when no text was entered by the user yet. If empty, the
 	// DefaultFilterPlaceholder is used. If Filter is nil, filtering is disabled
-	// and FilterPlaceholder does nothing.
+	// and FilterPlaceholder does nothing. It is rendered as part of the
+	// FilterInput template variable and is also available as the
+	// FilterPlaceholder template variable.
 	FilterPlaceholder string
 
 	// PageSize is the number of choices that are displayed at once. If PageSize
@@ -108,15 +261,117 @@ type Selection[T any] struct {
 	// navigating down from the last choice and the other way around.
 	LoopCursor bool
 
+	// CenterCursor keeps the selected choice vertically centered on the current
+	// page while navigating when pagination is active. Only near the
+	// beginning and the end of the list, the selected choice moves towards the
+	// edges of the page.
+	CenterCursor bool
+
+	// EnableBack enables the Back keys of the KeyMap. When a Back key is
+	// pressed, the prompt concludes with ErrBack. If a Back key is also a
+	// ClearFilter key, it only clears the filter as long as the filter is not
+	// empty.
+	EnableBack bool
+
+	// ExactMatchConfirm enables confirming a choice whose string
+	// representation exactly matches the filter text when a Select key is
+	// pressed. The exact match takes precedence over the currently selected
+	// choice. If multiple choices match exactly, the first one is confirmed. If
+	// no choice matches exactly, the currently selected choice is confirmed as
+	// usual.
+	ExactMatchConfirm bool
+
+	// EmptyEnterAborts makes a Select key abort the prompt with
+	// promptkit.ErrAborted instead of confirming a choice as long as the
+	// filter is empty and the user has not navigated through the choices. The
+	// choice that is initially selected is not considered a deliberate
+	// selection in this case, so it is only confirmed after the user moved
+	// the cursor or entered a filter.
+	EmptyEnterAborts bool
+
+	// DefaultChoice reports whether a value is the default choice. If it is
+	// set, the first default choice is selected initially and the templates
+	// can mark default choices using the IsDefault function.
+	DefaultChoice func(T) bool
+
+	// ResultContextLines is the number of choices before and after the final
+	// choice that are rendered in the default result template such that the
+	// final choice is shown in the context of its neighbors. The neighbors are
+	// taken from all choices regardless of the filter. If it is 0, only the
+	// final choice is rendered.
+	ResultContextLines int
+
+	// WithQuantities enables a mode in which each choice has an integer
+	// quantity that is adjusted with the Increment and Decrement keys of the
+	// KeyMap while the choice is selected. The quantities are available as the
+	// Quantity field of each choice and the final quantities can be obtained
+	// with RunPromptWithQuantities or Quantities.
+	WithQuantities bool
+
+	// QuantityBounds returns the minimum and maximum quantity for the given
+	// value if WithQuantities is enabled. The quantity of each choice starts at
+	// its minimum. If QuantityBounds is nil, the quantities range from 0 to
+	// math.MaxInt.
+	QuantityBounds func(T) (min int, max int)
+
+	// ShowMatchCount renders the total number of choices below the choices in
+	// the default template, followed by the number of choices that match the
+	// filter while a filter is entered.
+	ShowMatchCount bool
+
+	// Identity returns a unique identifier for a value. It is used to keep the
+	// selected choice and the quantities when the choices are replaced with
+	// Model.SetChoices or a ChoicesMsg. If Identity is nil, the selected
+	// choice and the quantities are kept by their index instead.
+	Identity func(T) string
+
+	// TooltipFunc returns a one-line tooltip for the value of the currently
+	// selected choice. If it is set, the tooltip is rendered below the choices
+	// in the default template and truncated to the terminal width. The
+	// tooltip is also available in custom templates as the Tooltip variable.
+	TooltipFunc func(T) string
+
+	// MaxLabelWidth truncates each choice in the list to the given width with
+	// an ellipsis while taking ANSI sequences into account. The full choice is
+	// still used for filtering and returned as the result. If it is 0, the
+	// choices are not truncated.
+	MaxLabelWidth int
+
+	// Header is rendered above the choices by the built-in templates, for
+	// example column titles for choices whose string representation consists
+	// of fixed-width columns. It is indented like the choices such that the
+	// columns align, it cannot be selected and it is not scrolled. The page
+	// size that is determined from the terminal height accounts for it.
+	Header string
+
+	// Clipboard enables copying the String of the selected choice with the
+	// Yank keys. While the indicator is shown after copying, the Copied
+	// template variable is true. If it is nil, the Yank keys are inactive.
+	Clipboard Clipboard
+
+	// RecentStore remembers the confirmed choices, which are identified by
+	// their Identity or, if Identity is nil, by their String, and ShowRecent
+	// floats up to ShowRecent of the most recently chosen ones to the top of
+	// the list where they are marked as recent, also when the choices are
+	// replaced with SetChoices. If ShowRecent is 0, the choices keep their
+	// order but the confirmed choice is still added to the RecentStore. If the
+	// RecentStore is nil, both options are inactive.
+	RecentStore RecentStore
+	ShowRecent  int
+
 	// Template holds the display template. A custom template can be used to
 	// completely customize the appearance of the selection prompt. If empty,
 	// the DefaultTemplate is used. The following variables and functions are
 	// available:
 	//
 	//  * Prompt string: The configured prompt.
+	//  * Icon string: The configured Icon.
 	//  * IsFiltered bool: Whether or not filtering is enabled.
 	//  * FilterPrompt string: The configured filter prompt.
+	//  * FilterAtBottom bool: Whether FilterPosition is FilterBottom.
 	//  * FilterInput string: The view of the filter input model.
+	//  * FilterPlaceholder string: The configured filter placeholder.
+	//  * Header string: The configured Header.
 	//  * Choices []*Choice: The choices on the current page.
 	//  * NChoices int: The number of choices on the current page.
 	//  * SelectedIndex int: The index that is currently selected.
@@ -124,18 +379,44 @@ type Selection[T any] struct {
 	//  * IsPaged bool: Whether pagination is currently active.
 	//  * AllChoices []*Choice: All configured choices.
 	//  * NAllChoices int: The number of configured choices.
+	//  * NMatchedChoices int: The number of choices that match the filter
+	//    across all pages.
+	//  * TotalCount int: The same as NAllChoices.
+	//  * FilteredCount int: The same as NMatchedChoices. Both counts are
+	//    available from the first render on, before anything is typed.
+	//  * IsNarrowed bool: Whether a filter text is entered.
+	//  * Narrowing bool: Whether filtering is pending due to FilterDebounce,
+	//    in which case the displayed choices do not match the filter yet.
+	//  * ShowMatchCount bool: The configured ShowMatchCount.
 	//  * TerminalWidth int: The width of the terminal.
-	//  * Selected(*Choice) string: The configured SelectedChoiceStyle.
-	//  * Unselected(*Choice) string: The configured UnselectedChoiceStyle.
+	//  * WithQuantities bool: Whether WithQuantities is enabled.
+	//  * Tooltip string: The tooltip for the currently selected choice as
+	//    returned by TooltipFunc or an empty string if TooltipFunc is nil.
+	//  * Copied bool: Whether a choice was just copied to the Clipboard.
+	//  * Selected(*Choice) string: The configured SelectedChoiceStyle
+	//    truncated to MaxLabelWidth and followed by the StyleFunc.
+	//  * Unselected(*Choice) string: The configured UnselectedChoiceStyle
+	//    truncated to MaxLabelWidth and followed by the StyleFunc.
 	//  * IsScrollDownHintPosition(idx int) bool: Returns whether
 	//    the scroll down hint should be displayed at the given index.
 	//  * IsScrollUpHintPosition(idx int) bool: Returns whether the
 	//    scroll up hint should be displayed at the given index).
+	//  * IsDefault(*Choice) bool: Returns whether the choice is a default
+	//    choice according to DefaultChoice.
 	//  * promptkit.UtilFuncMap: Handy helper functions.
 	//  * termenv TemplateFuncs (see https://github.com/muesli/termenv).
+	//  * The functions of the configured Theme, see
+	//    promptkit.Theme.TemplateFuncs.
 	//  * The functions specified in ExtendedTemplateFuncs.
 	Template string
 
+	// ListTemplate holds the template that is rendered by Model.ViewList. It is
+	// intended to only render the list of choices such that the selection can
+	// be embedded in a larger UI that provides its own prompt and filter. If
+	// empty, the DefaultListTemplate is used. The same variables and functions
+	// as in Template are available.
+	ListTemplate string
+
 	// ResultTemplate is rendered as soon as a choice has been selected.
 	// It is intended to permanently indicate the result of the prompt when the
 	// selection itself has disappeared. This template is only rendered in the
@@ -143,16 +424,33 @@ type Selection[T any] struct {
 	// following variables and functions are available:
 	//
 	//  * FinalChoice: The choice that was selected by the user.
+	//  * ContextChoices []*Choice: The final choice and up to
+	//    ResultContextLines choices before and after it. It is empty if
+	//    ResultContextLines is 0.
+	//  * ContextSelectedIndex int: The index of the final choice in
+	//    ContextChoices.
+	//  * WithQuantities bool: Whether WithQuantities is enabled.
+	//  * QuantityChoices []*Choice: All choices with a non-zero quantity if
+	//    WithQuantities is enabled.
 	//  * Prompt string: The configured prompt.
+	//  * ResultIcon string: The configured ResultIcon.
 	//  * AllChoices []*Choice: All configured choices.
 	//  * NAllChoices int: The number of configured choices.
 	//  * TerminalWidth int: The width of the terminal.
 	//  * Final(*Choice) string: The configured FinalChoiceStyle.
 	//  * promptkit.UtilFuncMap: Handy helper functions.
 	//  * termenv TemplateFuncs (see https://github.com/muesli/termenv).
+	//  * The functions of the configured Theme, see
+	//    promptkit.Theme.TemplateFuncs.
 	//  * The functions specified in ExtendedTemplateFuncs.
 	ResultTemplate string
 
+	// Theme colors the cursor, the selected choice and help texts such as hints and status
+	// messages in the built-in templates. Its empty colors fall back to the
+	// colors of the built-in templates and an empty HelpColor renders help
+	// texts faint.
+	Theme promptkit.Theme
+
 	// ExtendedTemplateFuncs can be used to add additional functions to the
 	// evaluation scope of the templates.
 	ExtendedTemplateFuncs template.FuncMap
@@ -180,6 +478,14 @@ type Selection[T any] struct {
 	// Custom templates may or may not use this function.
 	UnselectedChoiceStyle func(*Choice[T]) string
 
+	// StyleFunc allows to style individual choices based on their value and
+	// state, for example to render installed items in green. It is applied on
+	// top of the SelectedChoiceStyle and UnselectedChoiceStyle such that it
+	// composes with the default highlighting: The already styled choice is
+	// passed as RowState.Rendered and the returned string is rendered
+	// instead. If StyleFunc is nil, the choices are rendered unmodified.
+	StyleFunc func(T, RowState) string
+
 	// FinalChoiceStyle style allows to customize the appearance of the choice
 	// that was ultimately chosen. By default DefaultFinalChoiceStyle is used.
 	// If it is nil, no style will be applied and the plain string
@@ -188,23 +494,97 @@ type Selection[T any] struct {
 	// function.
 	FinalChoiceStyle func(*Choice[T]) string
 
+	// ResultDisplayFunc allows to display the chosen value differently in the
+	// result, for example with a friendly name instead of an identifier. It
+	// replaces the string representation of the choice that is passed to the
+	// FinalChoiceStyle by the template function Final while the value that is
+	// returned by RunPrompt remains unchanged. If it is nil, the string
+	// representation of the choice is displayed.
+	ResultDisplayFunc func(T) string
+
 	// KeyMap determines with which keys the selection prompt is controlled. By
 	// default, DefaultKeyMap is used.
 	KeyMap *KeyMap
 
 	// WrapMode decides which way the prompt view is wrapped if it does not fit
 	// the terminal. It can be a WrapMode provided by promptkit or a custom
-	// function. By default it is promptkit.WordWrap. It can also be nil which
-	// disables wrapping and likely causes output glitches.
+	// function. By default it is promptkit.PrefixHangingIndent such that wrapped
+	// lines stay aligned under the icon. It can also be nil which disables
+	// wrapping and likely causes output glitches.
 	WrapMode promptkit.WrapMode
 
+	// TrimBlankLines removes leading and trailing blank lines from the rendered
+	// view such that templates with surrounding whitespace do not produce stray
+	// empty lines. By default, the view is rendered unmodified.
+	TrimBlankLines bool
+
+	// AltScreen renders the prompt in the alternate screen buffer such that it
+	// does not scroll the terminal's scrollback. The result is printed to the
+	// normal screen after the prompt has concluded.
+	AltScreen bool
+
+	// EnableMouse enables mouse support. Clicking on a choice selects it,
+	// double-clicking confirms it and the scroll wheel scrolls the list. Only
+	// choices that the Template renders with Selected or Unselected can be
+	// clicked, any other text in the view is ignored. The mouse coordinates
+	// are interpreted relative to the top left corner of the view, so
+	// RunPrompt renders the prompt in the alternate screen buffer like with
+	// AltScreen and when the prompt is embedded as a widget, the mouse
+	// messages should be translated accordingly.
+	EnableMouse bool
+
+	// RecordKeys collects all key presses that are processed by the selection
+	// prompt if it is not nil. The recorded keys can be replayed with
+	// promptkit.ReplayKeys, for example to reproduce a reported bug.
+	RecordKeys *[]tea.KeyMsg
+
+	// InitialWidth and InitialHeight are the terminal size that is assumed
+	// instead of querying the terminal on startup until the terminal reports
+	// its actual size. Together with Input, Output and ColorProfile, they
+	// allow serving the prompt over connections where the terminal cannot be
+	// detected, such as SSH sessions. If both are 0, the terminal size is
+	// queried if Output is a terminal.
+	InitialWidth  int
+	InitialHeight int
+
+	// Managed disables everything the prompt does to the terminal or the program
+	// beyond rendering its view such that it can be embedded in a parent bubbletea
+	// program that owns the terminal. When set, the model does not return tea.Quit
+	// when the prompt concludes or aborts but a command that emits a DoneMsg with
+	// the result, it does not query the terminal size on startup and it does not
+	// clear the scroll area when the terminal is resized. The model stops
+	// updating after the conclusion and RunPrompt should not be used.
+	Managed bool
+
+	// ForwardUnhandledKeys makes the model emit a promptkit.UnhandledKeyMsg for
+	// key presses that match none of the bindings of the KeyMap and are not
+	// typed into the filter such that a parent program that embeds the prompt
+	// can handle them. By default, such keys are ignored, which is what
+	// RunPrompt expects.
+	ForwardUnhandledKeys bool
+
+	// LineEnding is used for the line breaks of the output that is written
+	// outside of the bubbletea renderer, which is the result that is printed
+	// after the AltScreen was left. Raw terminals that do not translate line
+	// feeds print such output like a staircase, which promptkit.CRLF avoids. By
+	// default, "\n" is used. The views rendered while the prompt is running
+	// always end their lines with "\r\n".
+	LineEnding string
+
+	// ProgramOptions are passed to tea.NewProgram when RunPrompt starts the
+	// interactive prompt, for example tea.WithoutSignalHandler. They are applied
+	// after the built-in options for Input, Output, AltScreen and EnableMouse
+	// such that they can override them.
+	ProgramOptions []tea.ProgramOption
+
 	// Output is the output writer, by default os.Stdout is used.
 	Output io.Writer
 	// Input is the input reader, by default, os.Stdin is used.
 	Input io.Reader
 
 	// ColorProfile determines how colors are rendered. By default, the terminal
-	// is queried.
+	// is queried. A profile that is forced with promptkit.SetColorProfile takes
+	// precedence and a single prompt cannot opt out of it.
 	ColorProfile termenv.Profile
 }
 
@@ -214,43 +594,105 @@ func New[T any](prompt string, choices []T) *Selection[T] {
 	return &Selection[T]{
 		choices:                     asChoices(choices),
 		Prompt:                      prompt,
-		FilterPrompt:                DefaultFilterPrompt,
+		FilterPrompt:                promptkit.CurrentStrings().FilterPrompt,
 		Template:                    DefaultTemplate,
+		ListTemplate:                DefaultListTemplate,
 		ResultTemplate:              DefaultResultTemplate,
 		Filter:                      FilterContainsCaseInsensitive[T],
 		FilterInputPlaceholderStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
 		SelectedChoiceStyle:         DefaultSelectedChoiceStyle[T],
 		FinalChoiceStyle:            DefaultFinalChoiceStyle[T],
 		KeyMap:                      NewDefaultKeyMap(),
-		FilterPlaceholder:           DefaultFilterPlaceholder,
+		FilterPlaceholder:           promptkit.CurrentStrings().FilterPlaceholder,
 		ExtendedTemplateFuncs:       template.FuncMap{},
-		WrapMode:                    promptkit.Truncate,
+		WrapMode:                    promptkit.PrefixHangingIndent,
 		Output:                      os.Stdout,
 		Input:                       os.Stdin,
 	}
 }
 
-// RunPrompt executes the selection prompt.
-func (s *Selection[T]) RunPrompt() (T, error) {
-	var zeroValue T
+// ValidateConfig checks the choices, the key map and the templates of the
+// selection prompt without running it.
+func (s *Selection[T]) ValidateConfig() error {
+	if len(s.choices) == 0 {
+		return fmt.Errorf("no choices provided")
+	}
+
+	if s.Template == "" {
+		return fmt.Errorf("empty template")
+	}
 
 	err := validateKeyMap(s.KeyMap)
 	if err != nil {
-		return zeroValue, fmt.Errorf("insufficient key map: %w", err)
+		return fmt.Errorf("insufficient key map: %w", err)
 	}
 
 	m := NewModel(s)
 
-	p := tea.NewProgram(m, tea.WithOutput(s.Output), tea.WithInput(s.Input))
+	_, err = m.initTemplate()
+	if err != nil {
+		return err
+	}
+
+	_, err = m.initListTemplate()
+	if err != nil {
+		return err
+	}
+
+	_, err = m.initResultTemplate()
 
-	_, err = p.Run()
+	return err
+}
+
+var _ promptkit.ConfigValidator = &Selection[any]{}
+
+// RunPrompt executes the selection prompt.
+func (s *Selection[T]) RunPrompt() (T, error) {
+	m, err := s.run()
 	if err != nil {
-		return zeroValue, fmt.Errorf("running prompt: %w", err)
+		var zeroValue T
+
+		return zeroValue, err
 	}
 
 	return m.Value()
 }
 
+// RunPromptWithQuantities executes the selection prompt with WithQuantities
+// enabled and returns the quantities of all choices with a non-zero quantity.
+// In contrast to RunPrompt, the quantities that were entered so far are also
+// returned if the prompt is aborted, alongside promptkit.ErrAborted or
+// promptkit.ErrInterrupted. The selection itself is not modified.
+func RunPromptWithQuantities[T comparable](s *Selection[T]) (map[T]int, error) {
+	withQuantities := *s
+	withQuantities.WithQuantities = true
+
+	m, err := withQuantities.run()
+	if err != nil {
+		return nil, err
+	}
+
+	return Quantities(m)
+}
+
+func (s *Selection[T]) run() (*Model[T], error) {
+	err := validateKeyMap(s.KeyMap)
+	if err != nil {
+		return nil, fmt.Errorf("insufficient key map: %w", err)
+	}
+
+	m := NewModel(s)
+
+	err = promptkit.Run(m, promptkit.WithOutput(s.Output), promptkit.WithInput(s.Input),
+		promptkit.WithAltScreen(s.AltScreen), promptkit.WithMouse(s.EnableMouse),
+		promptkit.WithLineEnding(s.LineEnding), promptkit.WithProgramOptions(s.ProgramOptions...))
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
 // FilterContainsCaseInsensitive returns true if the string representation of
 // the choice contains the filter string without regard for capitalization.
 func FilterContainsCaseInsensitive[T any](filter string, choice *Choice[T]) bool {
@@ -0,0 +1,149 @@
+package selection
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RecentStore remembers recently chosen items between runs of a selection, see
+// Selection.ShowRecent. Get returns the items starting with the most recent
+// one and Add marks an item as the most recent one.
+type RecentStore interface {
+	Get() ([]string, error)
+	Add(item string) error
+}
+
+// DefaultRecentLimit is the number of items that a MemoryRecentStore created
+// with NewMemoryRecentStore keeps.
+const DefaultRecentLimit = 100
+
+// MemoryRecentStore is a RecentStore that keeps the items in memory. It is safe
+// for concurrent use.
+type MemoryRecentStore struct {
+	// Limit is the maximum number of items that are kept, the least recent
+	// ones are dropped first. If it is 0 or negative, all items are kept.
+	Limit int
+
+	mu    sync.Mutex
+	items []string
+}
+
+var _ RecentStore = &MemoryRecentStore{}
+
+// NewMemoryRecentStore creates an empty in-memory RecentStore that keeps up to
+// DefaultRecentLimit items.
+func NewMemoryRecentStore() *MemoryRecentStore {
+	return &MemoryRecentStore{Limit: DefaultRecentLimit}
+}
+
+// Get returns the items starting with the most recent one.
+func (s *MemoryRecentStore) Get() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.items...), nil
+}
+
+// Add moves the item to the front or inserts it there if it was not chosen
+// before. If more than Limit items are stored, the least recent one is
+// dropped.
+func (s *MemoryRecentStore) Add(item string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := []string{item}
+
+	for _, existing := range s.items {
+		if existing != item {
+			items = append(items, existing)
+		}
+	}
+
+	if s.Limit > 0 && len(items) > s.Limit {
+		items = items[:s.Limit]
+	}
+
+	s.items = items
+
+	return nil
+}
+
+// loadRecent returns the items of the RecentStore if recent choices are shown.
+func (m *Model[T]) loadRecent() ([]string, error) {
+	if m.RecentStore == nil || m.ShowRecent <= 0 {
+		return nil, nil
+	}
+
+	recent, err := m.RecentStore.Get()
+	if err != nil {
+		return nil, fmt.Errorf("get recent choices: %w", err)
+	}
+
+	return recent, nil
+}
+
+// floatRecentChoices moves up to ShowRecent of the recently chosen choices to
+// the front in the order of the RecentStore and marks them as recent.
+func (m *Model[T]) floatRecentChoices() {
+	for _, choice := range m.choices {
+		choice.Recent = false
+	}
+
+	if len(m.recent) == 0 {
+		return
+	}
+
+	floated := make([]*Choice[T], 0, len(m.choices))
+
+	for _, item := range m.recent {
+		if len(floated) == m.ShowRecent {
+			break
+		}
+
+		for _, choice := range m.choices {
+			if m.recentKey(choice) == item && !choice.Recent {
+				choice.Recent = true
+				floated = append(floated, choice)
+
+				break
+			}
+		}
+	}
+
+	for _, choice := range m.choices {
+		if !choice.Recent {
+			floated = append(floated, choice)
+		}
+	}
+
+	m.choices = floated
+}
+
+// recentKey returns the item by which a choice is stored in the RecentStore,
+// which is its Identity or, if Identity is nil, its String.
+func (m *Model[T]) recentKey(choice *Choice[T]) string {
+	if m.Identity == nil {
+		return choice.String
+	}
+
+	return m.Identity(choice.Value)
+}
+
+// addRecentChoice adds the confirmed choice to the RecentStore.
+func (m *Model[T]) addRecentChoice() error {
+	if m.RecentStore == nil {
+		return nil
+	}
+
+	choice, err := m.ValueAsChoice()
+	if err != nil {
+		return err
+	}
+
+	err = m.RecentStore.Add(m.recentKey(choice))
+	if err != nil {
+		return fmt.Errorf("add recent choice: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package promptkit
+
+import "sync"
+
+// Strings holds the built-in texts of all prompts such that they can be
+// localized. The texts are used by the default templates and by the prompts
+// themselves, for example as defaults for configurable labels. Use SetStrings
+// to change them for all prompts at once.
+type Strings struct {
+	// Yes and No are the labels of the confirmation prompt.
+	Yes string
+	No  string
+
+	// FilterPrompt and FilterPlaceholder are the defaults for the filter of
+	// the selection prompt. They are applied when the prompt is created.
+	FilterPrompt      string
+	FilterPlaceholder string
+
+	// Narrowing is rendered by the selection while filtering is pending.
+	Narrowing string
+
+	// Copied is rendered by the selection after a choice was copied to the
+	// clipboard.
+	Copied string
+
+	// Validating and Retrying are rendered by the text input while the
+	// asynchronous validation is running or being retried.
+	Validating string
+	Retrying   string
+
+	// Paused is rendered by the text input while it is paused.
+	Paused string
+
+	// ConfirmAgain is rendered by the confirmation while Yes is armed and has
+	// to be confirmed again.
+	ConfirmAgain string
+
+	// YesAvailableIn is a format string with one %d verb for the remaining
+	// seconds that is rendered by the confirmation while Yes is locked.
+	YesAvailableIn string
+
+	// DefaultValueHint is a format string with one %s verb for the default
+	// value that is appended to the placeholder of the text input.
+	DefaultValueHint string
+
+	// NotAllowed is a format string with one %s verb for the pressed key that
+	// is rendered by the key press prompt when the key is not allowed.
+	NotAllowed string
+
+	// ChooseYesOrNo is a format string with two %s verbs for the labels of Yes
+	// and No that is rendered by the confirmation with Required when it is
+	// submitted without a decision.
+	ChooseYesOrNo string
+
+	// Items is a format string with one %d verb for the number of choices
+	// that is rendered by the selection with ShowMatchCount.
+	Items string
+
+	// Recent marks the choices of the selection that were floated to the top
+	// because they were chosen recently, see ShowRecent.
+	Recent string
+}
+
+// EnglishStrings returns the default English texts.
+func EnglishStrings() Strings {
+	return Strings{
+		Yes:               "Yes",
+		No:                "No",
+		FilterPrompt:      "Filter:",
+		FilterPlaceholder: "Type to filter choices",
+		Narrowing:         "narrowing...",
+		Copied:            "copied!",
+		Validating:        "validating...",
+		Retrying:          "retrying...",
+		Paused:            "(paused)",
+		ConfirmAgain:      "(confirm again)",
+		YesAvailableIn:    "(yes available in %ds)",
+		DefaultValueHint:  "[default: %s]",
+		NotAllowed:        "%s is not allowed",
+		ChooseYesOrNo:     "(please choose %s or %s)",
+		Items:             "%d items",
+		Recent:            "(recent)",
+	}
+}
+
+var (
+	stringsMu      sync.RWMutex
+	currentStrings = EnglishStrings()
+)
+
+// SetStrings replaces the built-in texts of all prompts, for example with a
+// translation. Texts that are empty in s fall back to the English default.
+// Prompts that are already created keep the defaults that were applied on
+// creation, so SetStrings should be called at program start. If s is nil, the
+// English texts are restored.
+func SetStrings(s *Strings) {
+	stringsMu.Lock()
+	defer stringsMu.Unlock()
+
+	currentStrings = EnglishStrings()
+	if s == nil {
+		return
+	}
+
+	english := currentStrings
+	currentStrings = *s
+
+	for _, field := range []struct {
+		value    *string
+		fallback string
+	}{
+		{&currentStrings.Yes, english.Yes},
+		{&currentStrings.No, english.No},
+		{&currentStrings.FilterPrompt, english.FilterPrompt},
+		{&currentStrings.FilterPlaceholder, english.FilterPlaceholder},
+		{&currentStrings.Narrowing, english.Narrowing},
+		{&currentStrings.Copied, english.Copied},
+		{&currentStrings.Validating, english.Validating},
+		{&currentStrings.Retrying, english.Retrying},
+		{&currentStrings.Paused, english.Paused},
+		{&currentStrings.ConfirmAgain, english.ConfirmAgain},
+		{&currentStrings.YesAvailableIn, english.YesAvailableIn},
+		{&currentStrings.DefaultValueHint, english.DefaultValueHint},
+		{&currentStrings.NotAllowed, english.NotAllowed},
+		{&currentStrings.ChooseYesOrNo, english.ChooseYesOrNo},
+		{&currentStrings.Items, english.Items},
+		{&currentStrings.Recent, english.Recent},
+	} {
+		if *field.value == "" {
+			*field.value = field.fallback
+		}
+	}
+}
+
+// CurrentStrings returns the built-in texts that are currently configured with
+// SetStrings. It is safe for concurrent use and is also available as the
+// Strings template function.
+func CurrentStrings() Strings {
+	stringsMu.RLock()
+	defer stringsMu.RUnlock()
+
+	return currentStrings
+}
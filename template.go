@@ -0,0 +1,39 @@
+package promptkit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/erikgeiser/promptkit/internal/templates"
+)
+
+var undefinedFuncRE = regexp.MustCompile(`function "([^"]+)" not defined`)
+
+// ParseTemplate parses the text as a template with the given name and adds the
+// functions of all function maps to the template's scope. Later function maps
+// take precedence over earlier ones. If the template calls a function that is
+// not defined in any of the function maps, the returned error names the
+// missing function and lists all available functions.
+func ParseTemplate(name string, text string, funcMaps ...template.FuncMap) (*template.Template, error) {
+	tmpl := template.New(name)
+
+	for _, funcMap := range funcMaps {
+		tmpl.Funcs(funcMap)
+	}
+
+	tmpl, err := tmpl.Parse(text)
+	if err == nil {
+		return tmpl, nil
+	}
+
+	match := undefinedFuncRE.FindStringSubmatch(err.Error())
+	if match == nil {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("template %q calls undefined function %q which can be "+
+		"added via ExtendedTemplateFuncs, available functions are %s: %w",
+		name, match[1], strings.Join(templates.FuncNames(funcMaps...), ", "), err)
+}
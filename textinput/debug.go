@@ -0,0 +1,66 @@
+package textinput
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/internal/templates"
+)
+
+// DebugConfig returns a human-readable description of the effective
+// configuration of the text input. If Hidden is set, the InitialValue and the
+// DefaultValue are redacted. It is intended to be included in bug reports and
+// its format is not stable.
+func (t *TextInput) DebugConfig() string {
+	var b strings.Builder
+
+	initialValue := fmt.Sprintf("%q", t.InitialValue)
+	defaultValue := fmt.Sprintf("%q", t.DefaultValue)
+
+	if t.Hidden {
+		initialValue = "<redacted>"
+		defaultValue = "<redacted>"
+	}
+
+	fmt.Fprintf(&b, "Prompt: %q\n", t.Prompt)
+	fmt.Fprintf(&b, "Icon: %q\n", t.Icon)
+	fmt.Fprintf(&b, "ResultIcon: %q\n", t.ResultIcon)
+	fmt.Fprintf(&b, "Placeholder: %q\n", t.Placeholder)
+	fmt.Fprintf(&b, "Hint: %q\n", t.Hint)
+	fmt.Fprintf(&b, "InitialValue: %s\n", initialValue)
+	fmt.Fprintf(&b, "RequireChange: %t\n", t.RequireChange)
+	fmt.Fprintf(&b, "DefaultValue: %s\n", defaultValue)
+	fmt.Fprintf(&b, "ShowDefaultInPlaceholder: %t\n", t.ShowDefaultInPlaceholder)
+	fmt.Fprintf(&b, "Validate: %t\n", t.Validate != nil)
+	fmt.Fprintf(&b, "AsyncValidate: %t\n", t.AsyncValidate != nil)
+	fmt.Fprintf(&b, "ValidateRetries: %d\n", t.ValidateRetries)
+	fmt.Fprintf(&b, "ValidateRetryDelay: %s\n", t.ValidateRetryDelay)
+	fmt.Fprintf(&b, "AutoComplete: %t\n", t.AutoComplete != nil)
+	fmt.Fprintf(&b, "SanitizeControlChars: %t\n", t.SanitizeControlChars)
+	fmt.Fprintf(&b, "Clipboard: %T\n", t.Clipboard)
+	fmt.Fprintf(&b, "Hidden: %t\n", t.Hidden)
+	fmt.Fprintf(&b, "HideMask: %q\n", t.HideMask)
+	fmt.Fprintf(&b, "MaskExceptLast: %d\n", t.MaskExceptLast)
+	fmt.Fprintf(&b, "AutoConfirmAtLength: %d\n", t.AutoConfirmAtLength)
+	fmt.Fprintf(&b, "CharLimit: %d\n", t.CharLimit)
+	fmt.Fprintf(&b, "InputWidth: %d\n", t.InputWidth)
+	fmt.Fprintf(&b, "Template: %s\n", templates.Name(t.Template, Templates))
+	fmt.Fprintf(&b, "ResultTemplate: %s\n", templates.Name(t.ResultTemplate, ResultTemplates))
+	fmt.Fprintf(&b, "Theme: %+v\n", t.Theme)
+	fmt.Fprintf(&b, "ExtendedTemplateFuncs: %s\n", templates.FuncNames(t.ExtendedTemplateFuncs))
+	fmt.Fprintf(&b, "KeyMap: %+v\n", t.KeyMap)
+	fmt.Fprintf(&b, "WrapMode: %t\n", t.WrapMode != nil)
+	fmt.Fprintf(&b, "TrimBlankLines: %t\n", t.TrimBlankLines)
+	fmt.Fprintf(&b, "AltScreen: %t\n", t.AltScreen)
+	fmt.Fprintf(&b, "InitialWidth: %d\n", t.InitialWidth)
+	fmt.Fprintf(&b, "Managed: %t\n", t.Managed)
+	fmt.Fprintf(&b, "ForwardUnhandledKeys: %t\n", t.ForwardUnhandledKeys)
+	fmt.Fprintf(&b, "LineEnding: %q\n", t.LineEnding)
+	fmt.Fprintf(&b, "ProgramOptions: %d\n", len(t.ProgramOptions))
+	fmt.Fprintf(&b, "Output: %T\n", t.Output)
+	fmt.Fprintf(&b, "Input: %T\n", t.Input)
+	fmt.Fprintf(&b, "ColorProfile: %d\n", promptkit.ResolveColorProfile(t.ColorProfile))
+
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package textinput
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/internal/headless"
+)
+
+// runHeadless reads a single line from the input instead of starting the
+// interactive prompt. The line is subject to the same DefaultValue and
+// validation rules as interactive input and the result is rendered to the
+// output.
+func (t *TextInput) runHeadless() (string, error) {
+	line, err := headless.ReadLine(t.Input)
+	if err != nil {
+		return "", err
+	}
+
+	m := NewModel(t)
+
+	m.Init()
+
+	if m.Err != nil {
+		return "", m.Err
+	}
+
+	if t.SanitizeControlChars {
+		line = string(sanitize([]rune(line)))
+	}
+
+	m.input.SetValue(line)
+
+	value := m.value()
+
+	if t.Validate != nil {
+		err = t.Validate(value)
+		if err != nil {
+			return "", fmt.Errorf("validate input: %w", err)
+		}
+	}
+
+	if t.RequireChange && value == t.InitialValue {
+		return "", ErrUnchanged
+	}
+
+	if t.AsyncValidate != nil {
+		err = t.AsyncValidate(value)
+		if err != nil {
+			return "", fmt.Errorf("validate input: %w", err)
+		}
+	}
+
+	m.quitting = true
+
+	view := m.View()
+	if m.Err != nil {
+		return "", m.Err
+	}
+
+	if view != "" {
+		_, err = io.WriteString(t.Output, promptkit.ConvertLineEndings(view, t.LineEnding))
+		if err != nil {
+			return "", fmt.Errorf("writing result: %w", err)
+		}
+	}
+
+	return m.Value()
+}
@@ -25,6 +25,7 @@ func NewDefaultKeyMap() *KeyMap {
 		Reset:                  []string{},
 		Submit:                 []string{"enter"},
 		Abort:                  []string{"ctrl+c"},
+		Interrupt:              []string{},
 	}
 }
 
@@ -41,7 +42,10 @@ var upsteamKeyMap = &KeyMap{
 	Paste:                  []string{"ctrl+v"},
 }
 
-// KeyMap defines the keys that trigger certain actions.
+// KeyMap defines the keys that trigger certain actions. The Abort keys abort
+// the prompt with promptkit.ErrAborted and the Interrupt keys abort it with
+// promptkit.ErrInterrupted such that the parent program can distinguish them.
+// By default, ctrl+c is an Abort key and no Interrupt keys are configured.
 type KeyMap struct {
 	MoveBackward           []string
 	MoveForward            []string
@@ -58,6 +62,7 @@ type KeyMap struct {
 	Reset                  []string
 	Submit                 []string
 	Abort                  []string
+	Interrupt              []string
 }
 
 func keyMatches(key tea.KeyMsg, mapping []string) bool {
@@ -82,8 +87,8 @@ func validateKeyMap(km *KeyMap) error {
 		return fmt.Errorf("no submit key")
 	}
 
-	if len(km.Abort) == 0 {
-		return fmt.Errorf("no abort key")
+	if len(km.Abort) == 0 && len(km.Interrupt) == 0 {
+		return fmt.Errorf("no abort or interrupt key")
 	}
 
 	return nil
@@ -104,6 +109,7 @@ func allKeys(km *KeyMap) (keys []string) {
 	keys = append(keys, km.Reset...)
 	keys = append(keys, km.Submit...)
 	keys = append(keys, km.Abort...)
+	keys = append(keys, km.Interrupt...)
 
 	return keys
 }
@@ -2,16 +2,43 @@ package textinput
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"strings"
 	"text/template"
+	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/internal/keys"
 	"github.com/muesli/termenv"
 )
 
+// DoneMsg is emitted by a Managed model when the prompt concludes or aborts
+// instead of quitting the program. Value and Err correspond to the result of
+// Model.Value. Afterwards, the model ignores all messages.
+type DoneMsg struct {
+	Value string
+	Err   error
+}
+
+// PauseMsg pauses the text input when it is sent to the model. While the text
+// input is paused, all key presses are ignored such that the parent program
+// can temporarily take over the keyboard.
+type PauseMsg struct{}
+
+// ResumeMsg resumes a text input that was paused with PauseMsg. The input and
+// the cursor position are preserved while the text input is paused.
+type ResumeMsg struct{}
+
+// asyncValidationMsg carries the result of an AsyncValidate run.
+type asyncValidationMsg struct {
+	attempt int
+	err     error
+}
+
 // Model implements the bubbletea.Model for a text input.
 type Model struct {
 	*TextInput
@@ -31,6 +58,11 @@ type Model struct {
 	autoCompleteTriggered  bool
 	autoCompleteIndecisive bool
 
+	validating         bool
+	retrying           bool
+	asyncValidationErr error
+
+	paused   bool
 	quitting bool
 
 	width int
@@ -46,14 +78,16 @@ func NewModel(textInput *TextInput) *Model {
 
 // Init initializes the text input model.
 func (m *Model) Init() tea.Cmd {
+	m.width = zeroAwareMin(m.InitialWidth, m.MaxWidth)
+
 	m.tmpl, m.Err = m.initTemplate()
 	if m.Err != nil {
-		return tea.Quit
+		return m.quit()
 	}
 
 	m.resultTmpl, m.Err = m.initResultTemplate()
 	if m.Err != nil {
-		return tea.Quit
+		return m.quit()
 	}
 
 	m.input = m.initInput()
@@ -62,18 +96,18 @@ func (m *Model) Init() tea.Cmd {
 }
 
 func (m *Model) initTemplate() (*template.Template, error) {
-	tmpl := template.New("view")
-	tmpl.Funcs(termenv.TemplateFuncs(m.ColorProfile))
-	tmpl.Funcs(promptkit.UtilFuncMap())
-	tmpl.Funcs(m.ExtendedTemplateFuncs)
-	tmpl.Funcs(template.FuncMap{
-		"Mask": m.mask,
-		"AutoCompleteSuggestions": func() []string {
-			return m.AutoComplete(m.input.Value())
+	return promptkit.ParseTemplate("view", m.Template,
+		termenv.TemplateFuncs(m.colorProfile()),
+		promptkit.UtilFuncMap(),
+		m.themeFuncs(),
+		m.ExtendedTemplateFuncs,
+		template.FuncMap{
+			"Mask": m.mask,
+			"AutoCompleteSuggestions": func() []string {
+				return m.AutoComplete(m.input.Value())
+			},
 		},
-	})
-
-	return tmpl.Parse(m.Template)
+	)
 }
 
 func (m *Model) initResultTemplate() (*template.Template, error) {
@@ -81,30 +115,44 @@ func (m *Model) initResultTemplate() (*template.Template, error) {
 		return nil, nil
 	}
 
-	tmpl := template.New("result")
-	tmpl.Funcs(termenv.TemplateFuncs(m.ColorProfile))
-	tmpl.Funcs(promptkit.UtilFuncMap())
-	tmpl.Funcs(m.ExtendedTemplateFuncs)
-	tmpl.Funcs(template.FuncMap{
-		"Mask": m.mask,
-	})
+	return promptkit.ParseTemplate("result", m.ResultTemplate,
+		termenv.TemplateFuncs(m.colorProfile()),
+		promptkit.UtilFuncMap(),
+		m.themeFuncs(),
+		m.ExtendedTemplateFuncs,
+		template.FuncMap{"Mask": m.mask},
+	)
+}
 
-	return tmpl.Parse(m.ResultTemplate)
+// themeFuncs returns the template functions of the Theme in which empty colors
+// fall back to the colors of the built-in templates.
+func (m *Model) themeFuncs() template.FuncMap {
+	return m.Theme.WithDefaults(defaultTheme).TemplateFuncs(m.colorProfile())
+}
+
+// colorProfile returns the configured ColorProfile unless another profile was
+// forced with promptkit.SetColorProfile.
+func (m *Model) colorProfile() termenv.Profile {
+	return promptkit.ResolveColorProfile(m.ColorProfile)
 }
 
 func (m *Model) initInput() textinput.Model {
 	input := textinput.New()
 	input.Prompt = ""
-	input.Placeholder = m.Placeholder
+	input.Placeholder = m.placeholder()
 	input.CharLimit = m.CharLimit
 	input.Width = m.InputWidth
 	input.TextStyle = m.InputTextStyle
 	input.PlaceholderStyle = m.InputPlaceholderStyle
 	input.Cursor.Style = m.InputCursorStyle
 
-	if m.Hidden {
+	switch {
+	case m.Hidden && m.HideMask == 0:
+		input.EchoMode = textinput.EchoNone
+	case m.Hidden:
 		input.EchoMode = textinput.EchoPassword
 		input.EchoCharacter = m.HideMask
+	default: // do nothing
 	}
 
 	input.SetValue(m.InitialValue)
@@ -115,34 +163,78 @@ func (m *Model) initInput() textinput.Model {
 
 // Update updates the model based on the received message.
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.Managed && m.quitting {
+		// the parent program was already notified with a DoneMsg
+		return m, nil
+	}
+
 	if m.Err != nil {
-		return m, tea.Quit
+		return m, m.quit()
 	}
 
 	var cmd tea.Cmd
 
+	if text, ok := msg.(PasteMsg); ok {
+		// pasted text is inserted like typed runes such that it is sanitized
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(string(text))}
+	}
+
+	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyRunes && m.SanitizeControlChars {
+		key.Runes = sanitize(key.Runes)
+		if len(key.Runes) == 0 {
+			return m, cmd
+		}
+
+		msg = key
+	}
+
 	switch msg := msg.(type) {
+	case PauseMsg:
+		m.paused = true
+		m.input.Blur()
+
+		return m, cmd
+	case ResumeMsg:
+		m.paused = false
+
+		return m, m.input.Focus()
+	case asyncValidationMsg:
+		return m, m.handleAsyncValidation(msg)
 	case tea.KeyMsg:
+		if m.paused {
+			return m, cmd
+		}
+
+		m.recordKey(msg)
+
+		if m.validating && !keyMatches(msg, m.KeyMap.Abort) && !keyMatches(msg, m.KeyMap.Interrupt) {
+			return m, cmd
+		}
+
+		m.asyncValidationErr = nil
 		m.autoCompleteTriggered = false
 		m.autoCompleteIndecisive = false
 
 		switch {
 		case keyMatches(msg, m.KeyMap.Submit):
-			if m.Validate == nil || m.Validate(m.input.Value()) == nil {
-				m.quitting = true
-
-				return m, tea.Quit
+			if m.Validate == nil || m.Validate(m.value()) == nil {
+				return m, m.submit()
 			}
 		case keyMatches(msg, m.KeyMap.AutoComplete):
 			if m.AutoComplete != nil {
 				m.input.SetValue(m.autoCompleteResult(m.input.Value()))
 				m.input.CursorEnd()
 			}
+		case keyMatches(msg, m.KeyMap.Interrupt):
+			m.Err = promptkit.ErrInterrupted
+			m.quitting = true
+
+			return m, m.quit()
 		case keyMatches(msg, m.KeyMap.Abort):
 			m.Err = promptkit.ErrAborted
 			m.quitting = true
 
-			return m, tea.Quit
+			return m, m.quit()
 		case keyMatches(msg, m.KeyMap.Reset):
 			m.input.SetValue(m.InitialValue)
 			m.input.CursorStart()
@@ -171,9 +263,15 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case keyMatches(msg, m.KeyMap.JumpToEnd):
 			msg.Type = tea.KeyEnd
 		case keyMatches(msg, m.KeyMap.Paste):
-			msg.Type = tea.KeyCtrlV
+			return m, m.paste()
 		case keyMatchesUpstreamKeyMap(msg):
+			if m.ForwardUnhandledKeys {
+				return m, promptkit.ForwardKey(msg)
+			}
+
 			return m, cmd // do not pass to bubbles/textinput
+		case m.ForwardUnhandledKeys && !keys.HandledByInput(m.input.KeyMap, msg):
+			return m, promptkit.ForwardKey(msg)
 		default: // do nothing
 		}
 	case tea.WindowSizeMsg:
@@ -181,14 +279,99 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case error:
 		m.Err = msg
 
-		return m, tea.Quit
+		return m, m.quit()
 	}
 
+	previousLength := utf8.RuneCountInString(m.input.Value())
+
 	m.input, cmd = m.input.Update(msg)
 
+	if m.reachedAutoConfirmLength(previousLength) {
+		return m, tea.Batch(cmd, m.submit())
+	}
+
 	return m, cmd
 }
 
+// submit concludes the prompt with the current value or starts the
+// asynchronous validation if AsyncValidate is configured. The value must
+// already have passed Validate. If RequireChange is set and the value was not
+// changed, the prompt concludes with ErrUnchanged instead.
+func (m *Model) submit() tea.Cmd {
+	if m.RequireChange && m.value() == m.InitialValue {
+		m.Err = ErrUnchanged
+		m.quitting = true
+
+		return m.quit()
+	}
+
+	if m.AsyncValidate != nil {
+		m.validating = true
+
+		return m.asyncValidate(0, 0)
+	}
+
+	m.quitting = true
+
+	return m.quit()
+}
+
+// reachedAutoConfirmLength returns whether the input just grew to exactly
+// AutoConfirmAtLength runes and passes Validate.
+func (m *Model) reachedAutoConfirmLength(previousLength int) bool {
+	if m.AutoConfirmAtLength <= 0 || m.validating || m.quitting {
+		return false
+	}
+
+	length := utf8.RuneCountInString(m.input.Value())
+	if length <= previousLength || length != m.AutoConfirmAtLength {
+		return false
+	}
+
+	return m.Validate == nil || m.Validate(m.value()) == nil
+}
+
+// asyncValidate returns a command that runs AsyncValidate on the current value
+// after the given delay.
+func (m *Model) asyncValidate(attempt int, delay time.Duration) tea.Cmd {
+	value := m.value()
+	validate := m.AsyncValidate
+
+	return func() tea.Msg {
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+
+		return asyncValidationMsg{attempt: attempt, err: validate(value)}
+	}
+}
+
+func (m *Model) handleAsyncValidation(msg asyncValidationMsg) tea.Cmd {
+	if !m.validating {
+		return nil
+	}
+
+	if msg.err == nil {
+		m.validating = false
+		m.retrying = false
+		m.quitting = true
+
+		return m.quit()
+	}
+
+	if msg.attempt < m.ValidateRetries && !errors.Is(msg.err, ErrInputValidation) {
+		m.retrying = true
+
+		return m.asyncValidate(msg.attempt+1, m.ValidateRetryDelay<<msg.attempt)
+	}
+
+	m.validating = false
+	m.retrying = false
+	m.asyncValidationErr = msg.err
+
+	return nil
+}
+
 // View renders the text input.
 func (m *Model) View() string {
 	if m.quitting {
@@ -211,18 +394,36 @@ func (m *Model) View() string {
 
 	var validationErr error
 	if m.Validate != nil {
-		validationErr = m.Validate(m.input.Value())
+		validationErr = m.Validate(m.value())
+	}
+
+	if validationErr == nil {
+		validationErr = m.asyncValidationErr
+	}
+
+	var structuredErr ValidationError
+
+	if validationErr != nil {
+		structuredErr, _ = asValidationError(validationErr)
 	}
 
 	err := m.tmpl.Execute(viewBuffer, map[string]interface{}{
 		"Prompt":                 m.Prompt,
+		"Icon":                   m.Icon,
 		"InitialValue":           m.InitialValue,
 		"Placeholder":            m.Placeholder,
-		"Input":                  m.input.View(),
+		"DefaultValue":           m.DefaultValue,
+		"Hint":                   promptkit.WordWrap(m.Hint, m.width),
+		"Input":                  m.inputView(),
 		"ValidationError":        validationErr,
+		"ValidationMessage":      structuredErr.Message,
+		"ValidationSuggestion":   structuredErr.Suggestion,
 		"TerminalWidth":          m.width,
 		"AutoCompleteTriggered":  m.autoCompleteTriggered,
 		"AutoCompleteIndecisive": m.autoCompleteIndecisive,
+		"Validating":             m.validating,
+		"Retrying":               m.retrying,
+		"Paused":                 m.paused,
 	})
 	if err != nil {
 		m.Err = err
@@ -251,9 +452,12 @@ func (m *Model) resultView() (string, error) {
 
 	err = m.resultTmpl.Execute(viewBuffer, map[string]interface{}{
 		"FinalValue":    value,
+		"Success":       m.Validate == nil || m.Validate(value) == nil,
 		"Prompt":        m.Prompt,
+		"ResultIcon":    m.ResultIcon,
 		"InitialValue":  m.InitialValue,
 		"Placeholder":   m.Placeholder,
+		"DefaultValue":  m.DefaultValue,
 		"Hidden":        m.Hidden,
 		"TerminalWidth": m.width,
 	})
@@ -264,7 +468,26 @@ func (m *Model) resultView() (string, error) {
 	return viewBuffer.String(), nil
 }
 
+// quit returns tea.Quit unless the prompt is managed by a parent program, in
+// which case it returns a command that emits a DoneMsg.
+func (m *Model) quit() tea.Cmd {
+	if m.Managed {
+		m.quitting = true
+		value, err := m.Value()
+
+		return func() tea.Msg {
+			return DoneMsg{Value: value, Err: err}
+		}
+	}
+
+	return tea.Quit
+}
+
 func (m *Model) wrap(text string) string {
+	if m.TrimBlankLines {
+		text = promptkit.TrimBlankLines(text)
+	}
+
 	if m.WrapMode == nil {
 		return text
 	}
@@ -272,18 +495,114 @@ func (m *Model) wrap(text string) string {
 	return m.WrapMode(text, m.width)
 }
 
-// Value returns the current value and error.
+// Value returns the current value and error. If the input is empty, the
+// DefaultValue is returned.
 func (m *Model) Value() (string, error) {
-	return m.input.Value(), m.Err
+	return m.value(), m.Err
 }
 
-// mask replaces each character with HideMask if Hidden is true.
+func (m *Model) value() string {
+	value := m.input.Value()
+	if value == "" {
+		return m.DefaultValue
+	}
+
+	return value
+}
+
+// asValidationError returns the ValidationError in the chain of err, which may
+// also be a pointer to a ValidationError.
+func asValidationError(err error) (ValidationError, bool) {
+	var validationErr ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr, true
+	}
+
+	var validationErrPtr *ValidationError
+	if errors.As(err, &validationErrPtr) && validationErrPtr != nil {
+		return *validationErrPtr, true
+	}
+
+	return ValidationError{}, false
+}
+
+// placeholder returns the configured placeholder, augmented with a default
+// value hint if ShowDefaultInPlaceholder is enabled.
+func (m *Model) placeholder() string {
+	if !m.ShowDefaultInPlaceholder || m.DefaultValue == "" {
+		return m.Placeholder
+	}
+
+	hint := fmt.Sprintf(promptkit.CurrentStrings().DefaultValueHint, m.DefaultValue)
+	if m.Placeholder == "" {
+		return hint
+	}
+
+	return m.Placeholder + " " + hint
+}
+
+// recordKey records the key for RecordKeys. If Hidden is true, typed and pasted
+// characters are replaced with HideMask, or DefaultMask if it is 0, such that
+// recorded keys never reveal the hidden value but can still be replayed.
+func (m *Model) recordKey(msg tea.KeyMsg) {
+	if m.Hidden && (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) {
+		maskChar := m.HideMask
+		if maskChar == 0 {
+			maskChar = DefaultMask
+		}
+
+		runes := make([]rune, len(msg.Runes))
+		for i := range runes {
+			runes[i] = maskChar
+		}
+
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: runes, Alt: msg.Alt}
+	}
+
+	keys.Record(m.RecordKeys, msg)
+}
+
+// mask replaces each character except for the last MaskExceptLast characters
+// with HideMask if Hidden is true. If HideMask is 0, the masked characters are
+// removed.
 func (m *Model) mask(s string) string {
 	if !m.Hidden {
 		return s
 	}
 
-	return strings.Repeat(string(m.HideMask), len(s))
+	maskChar := ""
+	if m.HideMask != 0 {
+		maskChar = string(m.HideMask)
+	}
+
+	if m.MaskExceptLast <= 0 {
+		return strings.Repeat(maskChar, utf8.RuneCountInString(s))
+	}
+
+	runes := []rune(s)
+
+	masked := len(runes) - m.MaskExceptLast
+	if masked <= 0 {
+		return s
+	}
+
+	return strings.Repeat(maskChar, masked) + string(runes[masked:])
+}
+
+// inputView renders the input field. The partially masked input cannot be
+// rendered with the echo modes of the input field, so in this case a copy of
+// the input field that holds the masked value is rendered instead.
+func (m *Model) inputView() string {
+	if !m.Hidden || m.MaskExceptLast <= 0 {
+		return m.input.View()
+	}
+
+	display := m.input
+	display.Validate = nil
+	display.EchoMode = textinput.EchoNormal
+	display.SetValue(m.mask(m.input.Value()))
+
+	return display.View()
 }
 
 func (m *Model) autoCompleteResult(input string) string {
@@ -1,14 +1,21 @@
 package textinput_test
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/erikgeiser/promptkit"
 	"github.com/erikgeiser/promptkit/test"
 	"github.com/erikgeiser/promptkit/textinput"
+	"github.com/muesli/reflow/ansi"
 	"github.com/muesli/termenv"
 )
 
@@ -76,6 +83,97 @@ func TestHidden(t *testing.T) {
 	test.AssertGoldenView(t, m, "hidden_confirmed.golden")
 }
 
+func TestHiddenMultiByte(t *testing.T) {
+	t.Parallel()
+
+	ti := textinput.New("password?")
+	ti.Hidden = true
+	ti.ResultTemplate = `{{ print .Prompt " " (Mask .FinalValue) }}`
+	m := textinput.NewModel(ti)
+
+	input := "pässwört"
+
+	test.Run(t, m, test.MsgsFromText(input)...)
+	assertNoError(t, m)
+
+	expected := strings.Repeat(string(textinput.DefaultMask), utf8.RuneCountInString(input))
+
+	strippedView := test.StripANSI(m.View())
+	if !strings.Contains(strippedView, expected) ||
+		strings.Count(strippedView, string(textinput.DefaultMask)) != utf8.RuneCountInString(input) {
+		t.Errorf("hidden view does not contain exactly one mask rune per character: %q", strippedView)
+	}
+
+	test.Update(t, m, tea.KeyEnter)
+
+	if view := m.View(); view != "password? "+expected {
+		t.Errorf("unexpected result view %q", view)
+	}
+
+	if value := getValue(t, m); value != input {
+		t.Errorf("unexpected value: %q, expected %q", value, input)
+	}
+}
+
+func TestHiddenWithoutMask(t *testing.T) {
+	t.Parallel()
+
+	ti := textinput.New("password?")
+	ti.Hidden = true
+	ti.HideMask = 0
+	ti.Template = `{{ .Prompt }} {{ .Input }}|`
+	ti.ResultTemplate = `{{ print .Prompt " " (Mask .FinalValue) }}|`
+	m := textinput.NewModel(ti)
+
+	test.Run(t, m, test.MsgsFromText("hunter2")...)
+	assertNoError(t, m)
+
+	if view := test.StripANSI(m.View()); view != "password?  |" {
+		t.Errorf("view renders the hidden input: %q", view)
+	}
+
+	test.Update(t, m, tea.KeyEnter)
+
+	if view := m.View(); view != "password? |" {
+		t.Errorf("unexpected result view %q", view)
+	}
+
+	if value := getValue(t, m); value != "hunter2" {
+		t.Errorf("unexpected value: %q, expected hunter2", value)
+	}
+}
+
+func TestMaskExceptLast(t *testing.T) {
+	t.Parallel()
+
+	m := textinput.NewModel(textinput.New("card number?"))
+	m.Hidden = true
+	m.HideMask = 'X'
+	m.MaskExceptLast = 4
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, test.MsgsFromText("12")...)
+	assertNoError(t, m)
+
+	if !strings.Contains(test.StripANSI(m.View()), "12") {
+		t.Errorf("short input was masked:\n%s", test.Indent(m.View()))
+	}
+
+	for _, msg := range test.MsgsFromText("345678") {
+		test.Update(t, m, msg)
+	}
+
+	strippedView := test.StripANSI(m.View())
+	if !strings.Contains(strippedView, "XXXX5678") || strings.Contains(strippedView, "1234") {
+		t.Errorf("input was not partially masked:\n%s", test.Indent(m.View()))
+	}
+
+	value := getValue(t, m)
+	if value != "12345678" {
+		t.Errorf("unexpected value: %q, expected %q", value, "12345678")
+	}
+}
+
 func TestPlaceholder(t *testing.T) {
 	t.Parallel()
 
@@ -130,6 +228,41 @@ func TestInitialValue(t *testing.T) {
 	test.AssertGoldenView(t, m, "initial_value_confirmed.golden")
 }
 
+func TestDefaultValue(t *testing.T) {
+	t.Parallel()
+
+	defaultValue := "foo"
+
+	m := textinput.NewModel(textinput.New("Text:"))
+	m.Placeholder = "enter some text"
+	m.DefaultValue = defaultValue
+	m.ShowDefaultInPlaceholder = true
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "default_value.golden")
+
+	view := m.View()
+	strippedView := test.StripANSI(view)
+
+	if !strings.Contains(strippedView, "[default: "+defaultValue+"]") {
+		t.Errorf("default value hint was not rendered in placeholder:\n%s", test.Indent(view))
+	}
+
+	value := getValue(t, m)
+	if value != defaultValue {
+		t.Errorf("value %q of empty input is not default value %q", value, defaultValue)
+	}
+
+	cmd := test.Update(t, m, tea.KeyEnter)
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Errorf("enter on empty input with default value did not produce quit signal")
+	}
+
+	test.AssertGoldenView(t, m, "default_value_confirmed.golden")
+}
+
 func TestModifiedInitialValue(t *testing.T) {
 	t.Parallel()
 
@@ -234,6 +367,256 @@ func TestSubmit(t *testing.T) {
 	test.AssertGoldenView(t, m, "submit.golden")
 }
 
+func TestResultSuccess(t *testing.T) {
+	t.Parallel()
+
+	m := textinput.NewModel(textinput.New("foo:"))
+	m.ResultTemplate = `{{ if .Success }}success{{ else }}failure{{ end }}`
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, test.KeyMsg('x'), tea.KeyEnter)
+	assertNoError(t, m)
+
+	if view := m.View(); view != "success" {
+		t.Errorf("unexpected result view for valid input: %q", view)
+	}
+}
+
+func TestAutoConfirmAtLength(t *testing.T) {
+	t.Parallel()
+
+	m := textinput.NewModel(textinput.New("code:"))
+	m.AutoConfirmAtLength = 4
+	m.ResultTemplate = `result: {{ .FinalValue }}`
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, test.MsgsFromText("1234")...)
+	assertNoError(t, m)
+
+	if view := m.View(); view != "result: 1234" {
+		t.Errorf("input was not confirmed at length 4: %q", view)
+	}
+}
+
+func TestAutoConfirmAtLengthBackspace(t *testing.T) {
+	t.Parallel()
+
+	m := textinput.NewModel(textinput.New("code:"))
+	m.AutoConfirmAtLength = 4
+	m.InitialValue = "12345"
+	m.ResultTemplate = `result: {{ .FinalValue }}`
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, tea.KeyBackspace)
+	assertNoError(t, m)
+
+	if view := m.View(); strings.HasPrefix(view, "result:") {
+		t.Errorf("input was confirmed while deleting: %q", view)
+	}
+
+	if value := getValue(t, m); value != "1234" {
+		t.Errorf("unexpected value %q", value)
+	}
+}
+
+func TestTrimBlankLines(t *testing.T) {
+	t.Parallel()
+
+	m := textinput.NewModel(textinput.New("foo:"))
+	m.Template = "\n  \n{{ .Prompt }}\n\n"
+	m.ResultTemplate = "\n{{ .FinalValue }}\n\n"
+	m.Validate = nil
+	m.TrimBlankLines = true
+
+	test.Run(t, m, test.MsgsFromText("bar")...)
+	assertNoError(t, m)
+
+	if view := m.View(); view != "foo:\n" {
+		t.Errorf("unexpected trimmed view: %q", view)
+	}
+
+	test.Update(t, m, tea.KeyEnter)
+
+	if view := m.View(); view != "bar\n" {
+		t.Errorf("unexpected trimmed result view: %q", view)
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	t.Parallel()
+
+	m := textinput.NewModel(textinput.New("foo:"))
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, test.MsgsFromText("bar")...)
+	test.Update(t, m, tea.KeyLeft)
+	test.Update(t, m, textinput.PauseMsg{})
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "paused.golden")
+
+	cmd := test.Update(t, m, tea.KeyEnter)
+	if cmd != nil {
+		t.Errorf("enter while paused did not produce a no-op")
+	}
+
+	test.Update(t, m, test.KeyMsg('x'))
+
+	if value := getValue(t, m); value != "bar" {
+		t.Errorf("input was modified while paused: %q", value)
+	}
+
+	test.Update(t, m, textinput.ResumeMsg{})
+	test.Update(t, m, test.KeyMsg('x'))
+
+	if value := getValue(t, m); value != "baxr" {
+		t.Errorf("input or cursor was not preserved while paused: %q", value)
+	}
+}
+
+func TestAsyncValidateRetries(t *testing.T) {
+	t.Parallel()
+
+	errTransient := errors.New("transient error")
+	calls := 0
+
+	m := textinput.NewModel(textinput.New("foo:"))
+	m.AsyncValidate = func(string) error {
+		calls++
+		if calls < 3 {
+			return errTransient
+		}
+
+		return nil
+	}
+	m.ValidateRetries = 2
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, test.KeyMsg('x'))
+	assertNoError(t, m)
+
+	cmd := test.Update(t, m, tea.KeyEnter)
+	if cmd == nil {
+		t.Fatalf("enter did not start asynchronous validation")
+	}
+
+	test.AssertGoldenView(t, m, "async_validating.golden")
+
+	cmd = test.Update(t, m, cmd())
+	if cmd == nil {
+		t.Fatalf("failed asynchronous validation was not retried")
+	}
+
+	view := test.StripANSI(m.View())
+	if !strings.Contains(view, "retrying...") {
+		t.Errorf("retry indicator was not rendered:\n%s", test.Indent(view))
+	}
+
+	cmd = test.Update(t, m, cmd())
+	if cmd == nil {
+		t.Fatalf("failed asynchronous validation was not retried a second time")
+	}
+
+	cmd = test.Update(t, m, cmd())
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Errorf("successful asynchronous validation did not produce quit signal")
+	}
+
+	if calls != 3 {
+		t.Errorf("unexpected number of asynchronous validations: %d", calls)
+	}
+}
+
+func TestAsyncValidateFinalError(t *testing.T) {
+	t.Parallel()
+
+	m := textinput.NewModel(textinput.New("foo:"))
+	m.AsyncValidate = func(string) error {
+		return fmt.Errorf("name is taken: %w", textinput.ErrInputValidation)
+	}
+	m.ValidateRetries = 2
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, test.KeyMsg('x'))
+
+	cmd := test.Update(t, m, tea.KeyEnter)
+	if cmd == nil {
+		t.Fatalf("enter did not start asynchronous validation")
+	}
+
+	cmd = test.Update(t, m, cmd())
+	if cmd != nil {
+		t.Errorf("final validation error was retried")
+	}
+
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "async_validation_error.golden")
+}
+
+func TestHint(t *testing.T) {
+	t.Parallel()
+
+	hint := "must be lowercase"
+
+	m := textinput.NewModel(textinput.New("name:"))
+	m.Hint = hint
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, test.MsgsFromText("foo")...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "hint.golden")
+
+	view := test.StripANSI(m.View())
+	if !strings.HasSuffix(view, "\n"+hint) {
+		t.Errorf("hint was not rendered below the input:\n%s", test.Indent(view))
+	}
+}
+
+func TestThemeHelp(t *testing.T) {
+	t.Parallel()
+
+	m := textinput.NewModel(textinput.New("name:"))
+	m.Hint = "must be lowercase"
+	m.Theme = promptkit.Theme{HelpColor: "240"}
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, test.MsgsFromText("foo")...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "theme_help.golden")
+
+	hint := termenv.TrueColor.String(m.Hint).Foreground(termenv.TrueColor.Color("240")).String()
+	if !strings.HasSuffix(m.View(), "\n"+hint) {
+		t.Errorf("hint was not rendered with the help color: %q", m.View())
+	}
+}
+
+func TestWrapWidths(t *testing.T) {
+	t.Parallel()
+
+	for _, width := range []int{20, 40, 80} {
+		width := width
+
+		t.Run(fmt.Sprint(width), func(t *testing.T) {
+			t.Parallel()
+
+			ti := textinput.New("Which files in the current directory should be deleted?")
+			ti.Icon = promptkit.DefaultIcons.Question
+			ti.InitialValue = "*.tmp"
+			ti.ColorProfile = termenv.TrueColor
+			m := textinput.NewModel(ti)
+
+			test.Run(t, m, tea.WindowSizeMsg{Width: width, Height: 20})
+			assertNoError(t, m)
+			test.AssertGoldenView(t, m, fmt.Sprintf("wrap_%d.golden", width))
+
+			for _, line := range strings.Split(strings.TrimSuffix(m.View(), "\n"), "\n") {
+				if lineWidth := ansi.PrintableRuneWidth(line); lineWidth > width {
+					t.Errorf("line %q with width %d exceeds terminal width", line, lineWidth)
+				}
+			}
+		})
+	}
+}
+
 func TestValidate(t *testing.T) {
 	t.Parallel()
 
@@ -256,6 +639,65 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidationError(t *testing.T) {
+	t.Parallel()
+
+	validate := func(s string) error {
+		if strings.ToLower(s) != s {
+			return textinput.ValidationError{
+				Message:    "must be lowercase",
+				Suggestion: "try " + strings.ToLower(s),
+				Code:       "E_CASE",
+			}
+		}
+
+		return nil
+	}
+
+	ti := textinput.New("name:")
+	ti.Validate = validate
+	ti.ColorProfile = termenv.TrueColor
+	m := textinput.NewModel(ti)
+
+	test.Run(t, m, test.MsgsFromText("Foo")...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "validation_error.golden")
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("create pipe: %v", err)
+	}
+
+	defer r.Close() //nolint:errcheck
+
+	_, err = io.WriteString(w, "Foo\n")
+	if err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	w.Close() //nolint:errcheck,gosec
+
+	ti = textinput.New("name:")
+	ti.Validate = validate
+	ti.Input = r
+	ti.Output = io.Discard
+
+	_, err = ti.RunPrompt()
+
+	var validationErr textinput.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+
+	if validationErr.Code != "E_CASE" {
+		t.Errorf("unexpected code %q", validationErr.Code)
+	}
+
+	if !errors.Is(err, textinput.ErrInputValidation) {
+		t.Errorf("ValidationError does not match ErrInputValidation")
+	}
+}
+
 func getValue(tb testing.TB, m *textinput.Model) string {
 	tb.Helper()
 
@@ -274,3 +716,358 @@ func assertNoError(tb testing.TB, m *textinput.Model) {
 		tb.Fatalf("model contains error: %v", m.Err)
 	}
 }
+
+func TestRecordKeysMasksHiddenValues(t *testing.T) {
+	t.Parallel()
+
+	var keys []tea.KeyMsg
+
+	ti := textinput.New("password:")
+	ti.Hidden = true
+	ti.RecordKeys = &keys
+	ti.ColorProfile = termenv.TrueColor
+	m := textinput.NewModel(ti)
+
+	test.Run(t, m, append(test.MsgsFromText("se cret"), tea.KeyBackspace)...)
+	assertNoError(t, m)
+
+	if len(keys) != 8 {
+		t.Fatalf("recorded %d instead of 8 keys", len(keys))
+	}
+
+	for _, key := range keys[:7] {
+		if string(key.Runes) != string(textinput.DefaultMask) {
+			t.Errorf("recorded key %q reveals the hidden value", key.String())
+		}
+	}
+
+	replayInput := textinput.New("password:")
+	replayInput.Hidden = true
+	replayModel := textinput.NewModel(replayInput)
+	replayModel.Init()
+
+	promptkit.ReplayKeys(replayModel, keys)
+
+	value, err := replayModel.Value()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if len([]rune(value)) != len("se cre") {
+		t.Errorf("replay produced %q which does not have the length of the input", value)
+	}
+}
+
+func TestDebugConfigRedactsHiddenValues(t *testing.T) {
+	t.Parallel()
+
+	ti := textinput.New("password:")
+	ti.InitialValue = "secret"
+	ti.Hidden = true
+
+	config := ti.DebugConfig()
+
+	if strings.Contains(config, "secret") {
+		t.Errorf("debug config of hidden input contains initial value:\n%s", test.Indent(config))
+	}
+
+	if !strings.Contains(config, "Template: default\n") {
+		t.Errorf("debug config does not name the default template:\n%s", test.Indent(config))
+	}
+}
+
+func TestRunPromptHeadless(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		input        string
+		defaultValue string
+		expected     string
+		expectErr    bool
+	}{
+		{name: "line", input: "foo\nbar\n", expected: "foo"},
+		{name: "crlf", input: "foo\r\n", expected: "foo"},
+		{name: "no newline", input: "foo", expected: "foo"},
+		{name: "default", input: "\n", defaultValue: "bar", expected: "bar"},
+		{name: "empty", input: "", defaultValue: "bar", expected: "bar"},
+		{name: "invalid", input: "\n", expectErr: true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("create pipe: %v", err)
+			}
+
+			defer r.Close() //nolint:errcheck
+
+			_, err = io.WriteString(w, testCase.input)
+			if err != nil {
+				t.Fatalf("write input: %v", err)
+			}
+
+			w.Close() //nolint:errcheck,gosec
+
+			output := &bytes.Buffer{}
+
+			ti := textinput.New("name:")
+			ti.DefaultValue = testCase.defaultValue
+			ti.ColorProfile = termenv.Ascii
+			ti.Input = r
+			ti.Output = output
+
+			value, err := ti.RunPrompt()
+
+			switch {
+			case testCase.expectErr && !errors.Is(err, textinput.ErrInputValidation):
+				t.Fatalf("expected validation error, got %v", err)
+			case testCase.expectErr:
+				return
+			case err != nil:
+				t.Fatalf("run prompt: %v", err)
+			}
+
+			if value != testCase.expected {
+				t.Errorf("expected value %q, got %q", testCase.expected, value)
+			}
+
+			expectedOutput := "name: " + testCase.expected + "\n"
+			if output.String() != expectedOutput {
+				t.Errorf("expected output %q, got %q", expectedOutput, output.String())
+			}
+		})
+	}
+}
+
+func TestRequireChange(t *testing.T) {
+	t.Parallel()
+
+	ti := textinput.New("new name:")
+	ti.InitialValue = "old"
+	ti.RequireChange = true
+	m := textinput.NewModel(ti)
+
+	test.Run(t, m, tea.KeyEnter)
+
+	if _, err := m.Value(); !errors.Is(err, textinput.ErrUnchanged) {
+		t.Fatalf("confirming the initial value produced %v instead of %q", err, textinput.ErrUnchanged)
+	}
+
+	m = textinput.NewModel(ti)
+
+	test.Run(t, m, append(test.MsgsFromText("er"), tea.KeyEnter)...)
+	assertNoError(t, m)
+
+	if value := getValue(t, m); value != "older" {
+		t.Errorf("unexpected value %q", value)
+	}
+}
+
+func TestManaged(t *testing.T) {
+	t.Parallel()
+
+	ti := textinput.New("name:")
+	ti.Managed = true
+	m := textinput.NewModel(ti)
+
+	test.Run(t, m, test.MsgsFromText("foo")...)
+
+	cmd := test.Update(t, m, tea.KeyEnter)
+	if cmd == nil {
+		t.Fatalf("managed model did not report its conclusion")
+	}
+
+	done, ok := cmd().(textinput.DoneMsg)
+	if !ok || done.Value != "foo" || done.Err != nil {
+		t.Errorf("unexpected conclusion %#v", done)
+	}
+
+	if cmd := test.Update(t, m, test.KeyMsg('x')); cmd != nil {
+		t.Errorf("concluded model still updates")
+	}
+
+	if value := getValue(t, m); value != "foo" {
+		t.Errorf("value changed after the conclusion to %q", value)
+	}
+}
+
+func TestForwardUnhandledKeys(t *testing.T) {
+	t.Parallel()
+
+	ti := textinput.New("foo:")
+	m := textinput.NewModel(ti)
+
+	test.Run(t, m)
+
+	cmd := test.Update(t, m, tea.KeyF1)
+	if cmd != nil {
+		t.Fatalf("unhandled key was forwarded without ForwardUnhandledKeys")
+	}
+
+	ti.ForwardUnhandledKeys = true
+
+	cmd = test.Update(t, m, tea.KeyF1)
+	if cmd == nil {
+		t.Fatalf("unhandled key was not forwarded")
+	}
+
+	expected := promptkit.UnhandledKeyMsg{Key: tea.KeyMsg{Type: tea.KeyF1}}
+	if msg := cmd(); !reflect.DeepEqual(msg, expected) {
+		t.Errorf("unexpected message %#v, expected %#v", msg, expected)
+	}
+
+	cmd = test.Update(t, m, tea.KeyMsg{Type: tea.KeyLeft, Alt: true})
+	if cmd != nil {
+		if _, ok := cmd().(promptkit.UnhandledKeyMsg); ok {
+			t.Errorf("handled key was forwarded")
+		}
+	}
+}
+
+func TestSanitizeControlChars(t *testing.T) {
+	t.Parallel()
+
+	paste := tea.KeyMsg{
+		Type:  tea.KeyRunes,
+		Runes: []rune("to\x00k\u200be\x1b[31mn\x1b]0;title\a\u202e!"),
+	}
+
+	m := textinput.NewModel(textinput.New("token?"))
+
+	test.Run(t, m, paste)
+	assertNoError(t, m)
+
+	if value := getValue(t, m); value != "token!" {
+		t.Errorf("unexpected sanitized value %q", value)
+	}
+
+	test.Update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'\x07'}})
+
+	if value := getValue(t, m); value != "token!" {
+		t.Errorf("control character was inserted: %q", value)
+	}
+
+	ti := textinput.New("token?")
+	ti.SanitizeControlChars = false
+	m = textinput.NewModel(ti)
+
+	test.Run(t, m, paste)
+	assertNoError(t, m)
+
+	if value := getValue(t, m); !strings.Contains(value, "\u200b") {
+		t.Errorf("input was sanitized without SanitizeControlChars: %q", value)
+	}
+}
+
+type fakeClipboard string
+
+func (c fakeClipboard) ReadAll() (string, error) {
+	return string(c), nil
+}
+
+func TestSanitizeClipboardPaste(t *testing.T) {
+	t.Parallel()
+
+	ti := textinput.New("token?")
+	ti.Clipboard = fakeClipboard("to\x1b[31mk‮en")
+	m := textinput.NewModel(ti)
+
+	test.Run(t, m, test.KeyMsg('>'))
+
+	cmd := test.Update(t, m, tea.KeyMsg{Type: tea.KeyCtrlV})
+	if cmd == nil {
+		t.Fatalf("paste key did not read the clipboard")
+	}
+
+	msg := cmd()
+	if msg != textinput.PasteMsg("to\x1b[31mk‮en") {
+		t.Fatalf("unexpected message %#v after reading the clipboard", msg)
+	}
+
+	test.Update(t, m, msg)
+	assertNoError(t, m)
+
+	if value := getValue(t, m); value != ">token" {
+		t.Errorf("unexpected sanitized value %q after pasting", value)
+	}
+}
+
+func TestProgramOptions(t *testing.T) {
+	t.Parallel()
+
+	output := &bytes.Buffer{}
+	override := &bytes.Buffer{}
+	applied := false
+
+	ti := textinput.New("name:")
+	ti.ColorProfile = termenv.Ascii
+	ti.Input = strings.NewReader("foo\r")
+	ti.Output = output
+	ti.ProgramOptions = []tea.ProgramOption{
+		tea.WithOutput(override),
+		func(*tea.Program) { applied = true },
+	}
+
+	_, err := ti.RunPrompt()
+	if err != nil {
+		t.Fatalf("run prompt: %v", err)
+	}
+
+	if !applied {
+		t.Errorf("program option was not applied")
+	}
+
+	if output.Len() != 0 {
+		t.Errorf("program option did not override the output: %q", output.String())
+	}
+
+	if !strings.Contains(override.String(), "name:") {
+		t.Errorf("text input was not rendered to the overridden output: %q",
+			override.String())
+	}
+}
+
+func TestHeadlessLineEnding(t *testing.T) {
+	t.Parallel()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("create pipe: %v", err)
+	}
+
+	defer r.Close() //nolint:errcheck
+
+	_, err = io.WriteString(w, "foo\n")
+	if err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	w.Close() //nolint:errcheck,gosec
+
+	output := &bytes.Buffer{}
+
+	ti := textinput.New("name:")
+	ti.ColorProfile = termenv.Ascii
+	ti.LineEnding = promptkit.CRLF
+	ti.Input = r
+	ti.Output = output
+
+	_, err = ti.RunPrompt()
+	if err != nil {
+		t.Fatalf("run prompt: %v", err)
+	}
+
+	if !strings.Contains(output.String(), "\r\n") {
+		t.Fatalf("output does not use CRLF line endings: %q", output.String())
+	}
+
+	if strings.Count(output.String(), "\n") != strings.Count(output.String(), "\r\n") {
+		t.Errorf("output contains bare line feeds: %q", output.String())
+	}
+}
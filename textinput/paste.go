@@ -0,0 +1,37 @@
+package textinput
+
+import (
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Clipboard reads text from the clipboard for the Paste keys. By default, the
+// system clipboard is used.
+type Clipboard interface {
+	ReadAll() (string, error)
+}
+
+// PasteMsg inserts the text at the cursor position of a running text input as
+// if it was pasted. It is also emitted by the Paste keys with the content of
+// the Clipboard. If SanitizeControlChars is enabled, the text is sanitized
+// like typed input.
+type PasteMsg string
+
+// paste returns a command that reads the Clipboard and emits its content as a
+// PasteMsg. Errors while reading the clipboard are ignored such that nothing
+// is pasted.
+func (m *Model) paste() tea.Cmd {
+	readAll := clipboard.ReadAll
+	if m.Clipboard != nil {
+		readAll = m.Clipboard.ReadAll
+	}
+
+	return func() tea.Msg {
+		text, err := readAll()
+		if err != nil || text == "" {
+			return nil
+		}
+
+		return PasteMsg(text)
+	}
+}
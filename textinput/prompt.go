@@ -10,10 +10,12 @@ import (
 	"io"
 	"os"
 	"text/template"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/internal/headless"
 	"github.com/muesli/termenv"
 )
 
@@ -21,16 +23,33 @@ const (
 	// DefaultTemplate defines the default appearance of the text input and can
 	// be copied as a starting point for a custom template.
 	DefaultTemplate = `
+	{{- if .Icon }}{{ print .Icon " " }}{{ end -}}
 	{{- Bold .Prompt }} {{ .Input -}}
-	{{- if .ValidationError }} {{ Foreground "1" (Bold "✘") }}
-	{{- else }} {{ Foreground "2" (Bold "✔") }}
+	{{- if .ValidationError }} {{ ThemeError (Bold "✘") }}
+	{{- else }} {{ ThemeSuccess (Bold "✔") }}
+	{{- end -}}
+	{{- if .ValidationMessage }} {{ ThemeError .ValidationMessage }}
+	{{- if .ValidationSuggestion }} {{ ThemeHelp .ValidationSuggestion }}{{ end -}}
+	{{- end -}}
+	{{- if .Retrying }} {{ ThemeHelp (Strings).Retrying }}
+	{{- else if .Validating }} {{ ThemeHelp (Strings).Validating }}
+	{{- end -}}
+	{{- if .Paused }} {{ ThemeHelp (Strings).Paused }}
+	{{- end -}}
+	{{- if .Hint }}
+	{{- print "\n" (ThemeHelp .Hint) -}}
 	{{- end -}}
 	`
 
 	// DefaultResultTemplate defines the default appearance with which the
 	// finale result of the prompt is presented.
 	DefaultResultTemplate = `
-	{{- print .Prompt " " (Foreground "32"  (Mask .FinalValue)) "\n" -}}
+	{{- if .ResultIcon }}{{ print .ResultIcon " " }}{{ end -}}
+	{{- if .Success -}}
+		{{- print .Prompt " " (ThemeSelected (Mask .FinalValue)) "\n" -}}
+	{{- else -}}
+		{{- print .Prompt " " (ThemeError (Mask .FinalValue)) "\n" -}}
+	{{- end -}}
 	`
 
 	// DefaultMask specified the character with which the input is masked by
@@ -38,31 +57,126 @@ const (
 	DefaultMask = '●'
 )
 
+// defaultTheme holds the colors of the built-in templates which are used for
+// the empty colors of the Theme.
+var defaultTheme = promptkit.Theme{SelectedColor: "32", SuccessColor: "2", ErrorColor: "1"}
+
+// Templates holds all built-in templates by name such that they can be
+// selected by a string, for example from a configuration file. The matching
+// result templates are stored under the same name in ResultTemplates.
+var Templates = map[string]string{
+	"default": DefaultTemplate,
+}
+
+// ResultTemplates holds all built-in result templates by name. The names
+// correspond to the templates in Templates.
+var ResultTemplates = map[string]string{
+	"default": DefaultResultTemplate,
+}
+
 // ErrInputValidation is a generic input validation error. For more detailed
-// diagnosis, feel free to return any custom error instead.
+// diagnosis, feel free to return any custom error or a ValidationError instead.
 var ErrInputValidation = fmt.Errorf("validation error")
 
+// ErrUnchanged is returned if RequireChange is set and the confirmed value is
+// equal to the InitialValue.
+var ErrUnchanged = fmt.Errorf("value unchanged")
+
+// ValidationError is a validation error that can be returned by Validate and
+// AsyncValidate to provide a message and a suggested fix that are displayed by
+// the default template as well as an error code for the caller. It matches
+// ErrInputValidation with errors.Is. When RunPrompt reads the value from a
+// non-terminal input, a failed validation returns it such that it can be
+// inspected with errors.As.
+type ValidationError struct {
+	Message    string
+	Suggestion string
+	Code       string
+}
+
+// Error returns the message of the validation error, prefixed by the code if
+// it is set.
+func (e ValidationError) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+
+	return e.Code + ": " + e.Message
+}
+
+// Is returns true for ErrInputValidation.
+func (e ValidationError) Is(target error) bool {
+	return target == ErrInputValidation
+}
+
 // TextInput represents a configurable selection prompt.
 type TextInput struct {
 	// Prompt holds the prompt text or question that is printed above the
 	// choices in the default template (if not empty).
 	Prompt string
 
+	// Icon is rendered in front of the prompt by the built-in templates, for
+	// example promptkit.DefaultIcons.Question. ResultIcon is rendered in front
+	// of the prompt by the built-in result templates, for example
+	// promptkit.DefaultIcons.Success. By default, no icons are rendered.
+	Icon       string
+	ResultIcon string
+
 	// Placeholder holds the text that is displayed in the input field when the
 	// input data is empty, e.g. when no text was entered yet.
 	Placeholder string
 
+	// Hint holds a text that is displayed below the input field by the
+	// default template. In contrast to the Placeholder, it remains visible
+	// while the user is typing, which is useful to describe the expected
+	// input format.
+	Hint string
+
 	// InitialValue is similar to Placeholder, however, the actual input data is
 	// set to InitialValue such that as if it was entered by the user. This can
 	// be used to provide an editable default value.
 	InitialValue string
 
+	// RequireChange treats confirming a value that is equal to the
+	// InitialValue as a cancellation, for example in rename flows, such that
+	// ErrUnchanged is returned instead of the value.
+	RequireChange bool
+
+	// DefaultValue is the value that is used when the input is submitted while
+	// the input data is empty. In contrast to InitialValue, it is not editable
+	// and does not have to be deleted by the user in order to enter a
+	// different value.
+	DefaultValue string
+
+	// ShowDefaultInPlaceholder specifies whether a hint such as "[default:
+	// foo]" should be generated from DefaultValue and appended to the
+	// Placeholder. It has no effect if DefaultValue is empty.
+	ShowDefaultInPlaceholder bool
+
 	// Validate is a function that validates whether the current input data is
 	// valid. If it is not, the data cannot be submitted. By default, Validate
 	// ensures that the input data is not empty. If Validate is set to nil, no
 	// validation is performed.
 	Validate func(string) error
 
+	// AsyncValidate is a function that validates the input data when it is
+	// submitted, after it passed Validate. In contrast to Validate, it runs in
+	// the background such that it can perform slow operations like network
+	// requests. While it runs, further input is ignored. If it returns an
+	// error, the error is displayed and the data is not submitted. If
+	// AsyncValidate is nil, no asynchronous validation is performed.
+	AsyncValidate func(string) error
+
+	// ValidateRetries is the number of times AsyncValidate is retried when it
+	// fails with an error that does not wrap ErrInputValidation, for example
+	// due to a transient network error. Errors that wrap ErrInputValidation
+	// are considered final and are never retried.
+	ValidateRetries int
+
+	// ValidateRetryDelay is the delay before the first retry of AsyncValidate.
+	// The delay is doubled for each subsequent retry.
+	ValidateRetryDelay time.Duration
+
 	// AutoComplete is a function that suggests multiple candidates for
 	// auto-completion based on a given input. If it returns only a single
 	// candidate, this candidate is auto-completed. If it returns multiple
@@ -72,14 +186,41 @@ type TextInput struct {
 	// auto-completion is performed.
 	AutoComplete func(string) []string
 
+	// SanitizeControlChars removes terminal escape sequences as well as
+	// control and format characters such as zero-width spaces from typed and
+	// pasted input before it enters the input field such that they can
+	// neither corrupt the terminal nor end up in the value. Tabs and line
+	// breaks are replaced with spaces regardless of this option. It is
+	// enabled by default.
+	SanitizeControlChars bool
+
+	// Clipboard is read by the Paste keys. If it is nil, the system clipboard
+	// is used. The pasted text is inserted like a PasteMsg.
+	Clipboard Clipboard
+
 	// Hidden specified whether or not the input data is considered secret and
 	// should be masked. This is useful for password prompts.
 	Hidden bool
 
 	// HideMask specified the character with which the input data should be
-	// masked when Hidden is set to true.
+	// masked when Hidden is set to true. If it is 0, the input is not rendered
+	// at all, not even its length.
 	HideMask rune
 
+	// MaskExceptLast leaves the last MaskExceptLast characters of the input
+	// unmasked if Hidden is set to true, for example to show the last four
+	// digits of a card number. If the input is shorter, it is not masked at
+	// all. The value of the prompt is always the full input.
+	MaskExceptLast int
+
+	// AutoConfirmAtLength confirms the input as soon as it grows to exactly
+	// the given number of runes and passes Validate, without requiring a
+	// Submit key, which is useful for fixed-length codes. Deleting characters
+	// never confirms the input and neither does pasting text that makes the
+	// input longer than AutoConfirmAtLength. If it is 0 or less, the input is
+	// only confirmed with a Submit key.
+	AutoConfirmAtLength int
+
 	// CharLimit is the maximum amount of characters this input element will
 	// accept. If 0 or less, there's no limit.
 	CharLimit int
@@ -95,14 +236,27 @@ type TextInput struct {
 	// available:
 	//
 	//  * Prompt string: The configured prompt.
+	//  * Icon string: The configured Icon.
 	//  * InitialValue string: The configured initial value of the input.
 	//  * Placeholder string: The configured placeholder of the input.
+	//  * DefaultValue string: The configured default value of the input.
+	//  * Hint string: The configured hint, word-wrapped to the terminal
+	//    width.
 	//  * Input string: The actual input field.
-	//  * ValidationError error: The error value returned by Validate.
-	//    to the configured Validate function.
+	//  * ValidationError error: The error value returned by Validate or by
+	//    the last run of AsyncValidate.
+	//  * ValidationMessage string: The Message of the validation error if it
+	//    is a ValidationError.
+	//  * ValidationSuggestion string: The Suggestion of the validation error
+	//    if it is a ValidationError.
+	//  * Validating bool: Whether or not AsyncValidate is currently running.
+	//  * Retrying bool: Whether or not AsyncValidate is currently retried.
 	//  * TerminalWidth int: The width of the terminal.
+	//  * Paused bool: Whether or not the input is paused by a PauseMsg.
 	//  * promptkit.UtilFuncMap: Handy helper functions.
 	//  * termenv TemplateFuncs (see https://github.com/muesli/termenv).
+	//  * The functions of the configured Theme, see
+	//    promptkit.Theme.TemplateFuncs.
 	//  * The functions specified in ExtendedTemplateFuncs.
 	Template string
 
@@ -112,10 +266,14 @@ type TextInput struct {
 	// method and NOT when the text input is used as a model. The following
 	// variables and functions are available:
 	//
-	//  * FinalChoice: The choice that was selected by the user.
+	//  * FinalValue string: The value that was entered by the user.
+	//  * Success bool: Whether or not the final value passed validation.
+	//  * Hidden bool: Whether or not the input is hidden.
 	//  * Prompt string: The configured prompt.
+	//  * ResultIcon string: The configured ResultIcon.
 	//  * InitialValue string: The configured initial value of the input.
 	//  * Placeholder string: The configured placeholder of the input.
+	//  * DefaultValue string: The configured default value of the input.
 	//  * TerminalWidth int: The width of the terminal.
 	//  * AutoCompleteTriggered bool: An indication that auto-complete was
 	//    just triggered by the user. It resets after further input.
@@ -125,13 +283,22 @@ type TextInput struct {
 	//  * AutoCompleteSuggestions() []string: A function that returns the
 	//    auto-complete suggestions for the current input.
 	//  * Mask(string) string: A function that replaces all characters of
-	//    a string with the character specified in HideMask if Hidden is
-	//    true and returns the input string if Hidden is false.
+	//    a string except for the last MaskExceptLast characters with the
+	//    character specified in HideMask if Hidden is true and returns the
+	//    input string if Hidden is false.
 	//  * promptkit.UtilFuncMap: Handy helper functions.
 	//  * termenv TemplateFuncs (see https://github.com/muesli/termenv).
+	//  * The functions of the configured Theme, see
+	//    promptkit.Theme.TemplateFuncs.
 	//  * The functions specified in ExtendedTemplateFuncs.
 	ResultTemplate string
 
+	// Theme colors the validation indicators, the error message, the final value
+	// and help texts such as hints and status messages in the built-in
+	// templates. Its empty colors fall back to the colors of the built-in
+	// templates and an empty HelpColor renders help texts faint.
+	Theme promptkit.Theme
+
 	// ExtendedTemplateFuncs can be used to add additional functions to the
 	// evaluation scope of the templates.
 	ExtendedTemplateFuncs template.FuncMap
@@ -151,17 +318,74 @@ type TextInput struct {
 
 	// WrapMode decides which way the prompt view is wrapped if it does not fit
 	// the terminal. It can be a WrapMode provided by promptkit or a custom
-	// function. By default it is promptkit.WordWrap. It can also be nil which
-	// disables wrapping and likely causes output glitches.
+	// function. By default it is promptkit.PrefixHangingIndent such that wrapped
+	// lines stay aligned under the icon. It can also be nil which disables
+	// wrapping and likely causes output glitches.
 	WrapMode promptkit.WrapMode
 
+	// TrimBlankLines removes leading and trailing blank lines from the rendered
+	// view such that templates with surrounding whitespace do not produce stray
+	// empty lines. By default, the view is rendered unmodified.
+	TrimBlankLines bool
+
+	// AltScreen renders the prompt in the alternate screen buffer such that it
+	// does not scroll the terminal's scrollback. The result is printed to the
+	// normal screen after the prompt has concluded.
+	AltScreen bool
+
+	// RecordKeys collects all key presses that are processed by the text input
+	// if it is not nil. The recorded keys can be replayed with
+	// promptkit.ReplayKeys, for example to reproduce a reported bug. If Hidden
+	// is set, typed characters are recorded as the HideMask character.
+	RecordKeys *[]tea.KeyMsg
+
+	// InitialWidth is the terminal width that is assumed until the terminal
+	// reports its actual size. Together with Input, Output and ColorProfile,
+	// it allows serving the prompt over connections where the terminal cannot be
+	// detected, such as SSH sessions. If it is 0, the width is unknown until
+	// the first tea.WindowSizeMsg.
+	InitialWidth int
+
+	// Managed disables everything the prompt does to the terminal or the program
+	// beyond rendering its view such that it can be embedded in a parent bubbletea
+	// program that owns the terminal. When set, the model does not return tea.Quit
+	// when the prompt concludes or aborts but a command that emits a DoneMsg with
+	// the result such that the parent program can decide what to do next. The
+	// model stops updating afterwards and RunPrompt should not be used.
+	Managed bool
+
+	// ForwardUnhandledKeys makes the model emit a promptkit.UnhandledKeyMsg for
+	// key presses that match none of the bindings of the KeyMap and are not
+	// handled by the input field such that a parent program that embeds the
+	// prompt can handle them. By default, such keys are ignored, which is what
+	// RunPrompt expects.
+	ForwardUnhandledKeys bool
+
+	// LineEnding is used for the line breaks of the output that is written
+	// outside of the bubbletea renderer, which are the result of a headless
+	// text input and the result that is printed after the AltScreen was left.
+	// Raw terminals that do not translate line feeds print such output like a
+	// staircase, which promptkit.CRLF avoids. By default, "\n" is used. The
+	// views rendered while the prompt is running always end their lines with
+	// "\r\n".
+	LineEnding string
+
+	// ProgramOptions are passed to tea.NewProgram when RunPrompt starts the
+	// interactive prompt, for example tea.WithoutSignalHandler. They are applied
+	// after the built-in options for Input, Output and AltScreen such that they
+	// can override them.
+	ProgramOptions []tea.ProgramOption
+
 	// Output is the output writer, by default os.Stdout is used.
 	Output io.Writer
-	// Input is the input reader, by default, os.Stdin is used.
+	// Input is the input reader, by default, os.Stdin is used. If it is a file
+	// that is not a terminal, RunPrompt reads a single line as the value
+	// without starting the interactive prompt.
 	Input io.Reader
 
 	// ColorProfile determines how colors are rendered. By default, the terminal
-	// is queried.
+	// is queried. A profile that is forced with promptkit.SetColorProfile takes
+	// precedence and a single prompt cannot opt out of it.
 	ColorProfile termenv.Profile
 }
 
@@ -176,27 +400,56 @@ func New(prompt string) *TextInput {
 		InputPlaceholderStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
 		Validate:              ValidateNotEmpty,
 		HideMask:              DefaultMask,
+		SanitizeControlChars:  true,
 		ExtendedTemplateFuncs: template.FuncMap{},
-		WrapMode:              promptkit.Truncate,
+		WrapMode:              promptkit.PrefixHangingIndent,
 		Output:                os.Stdout,
 		Input:                 os.Stdin,
 	}
 }
 
-// RunPrompt executes the text input prompt.
+// ValidateConfig checks the key map and the templates of the text input
+// without running it.
+func (t *TextInput) ValidateConfig() error {
+	err := validateKeyMap(t.KeyMap)
+	if err != nil {
+		return fmt.Errorf("insufficient key map: %w", err)
+	}
+
+	m := NewModel(t)
+
+	_, err = m.initTemplate()
+	if err != nil {
+		return err
+	}
+
+	_, err = m.initResultTemplate()
+
+	return err
+}
+
+var _ promptkit.ConfigValidator = &TextInput{}
+
+// RunPrompt executes the text input prompt. If the Input is a file that is not
+// a terminal, such as a pipe, a single line is read as the value instead and
+// the same DefaultValue and validation rules apply.
 func (t *TextInput) RunPrompt() (string, error) {
 	err := validateKeyMap(t.KeyMap)
 	if err != nil {
 		return "", fmt.Errorf("insufficient key map: %w", err)
 	}
 
-	m := NewModel(t)
+	if !headless.Interactive(t.Input) {
+		return t.runHeadless()
+	}
 
-	p := tea.NewProgram(m, tea.WithOutput(t.Output), tea.WithInput(t.Input))
+	m := NewModel(t)
 
-	_, err = p.Run()
+	err = promptkit.Run(m, promptkit.WithOutput(t.Output), promptkit.WithInput(t.Input),
+		promptkit.WithAltScreen(t.AltScreen),
+		promptkit.WithLineEnding(t.LineEnding), promptkit.WithProgramOptions(t.ProgramOptions...))
 	if err != nil {
-		return "", fmt.Errorf("running prompt: %w", err)
+		return "", err
 	}
 
 	return m.Value()
@@ -0,0 +1,63 @@
+package textinput
+
+import (
+	"unicode"
+)
+
+// sanitize removes terminal escape sequences as well as control and format
+// characters such as zero-width spaces or bidirectional overrides from the
+// runes. Tabs and line breaks are kept such that the input field can replace
+// them with spaces.
+func sanitize(runes []rune) []rune {
+	sanitized := make([]rune, 0, len(runes))
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\x1b':
+			i = skipEscapeSequence(runes, i)
+		case r == '\t' || r == '\n' || r == '\r':
+			sanitized = append(sanitized, r)
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			// drop the character
+		default:
+			sanitized = append(sanitized, r)
+		}
+	}
+
+	return sanitized
+}
+
+// skipEscapeSequence returns the index of the last rune of the escape
+// sequence that starts at the given index. CSI sequences end with a final
+// byte and OSC sequences with BEL or ST, for all other sequences only the
+// escape character itself and the following rune are skipped.
+func skipEscapeSequence(runes []rune, start int) int {
+	if start+1 >= len(runes) {
+		return start
+	}
+
+	switch runes[start+1] {
+	case '[':
+		for i := start + 2; i < len(runes); i++ {
+			if runes[i] >= 0x40 && runes[i] <= 0x7e {
+				return i
+			}
+		}
+	case ']':
+		for i := start + 2; i < len(runes); i++ {
+			if runes[i] == '\a' {
+				return i
+			}
+
+			if runes[i] == '\x1b' && i+1 < len(runes) && runes[i+1] == '\\' {
+				return i + 1
+			}
+		}
+	default:
+		return start + 1
+	}
+
+	return len(runes) - 1
+}
@@ -0,0 +1,74 @@
+package promptkit
+
+import (
+	"text/template"
+
+	"github.com/muesli/termenv"
+)
+
+// Theme holds the colors that are shared by a suite of prompts such that their
+// appearance can be kept consistent without copying templates. The colors
+// accept the same values as the Foreground template function, for example
+// "32" or "#ff0000". Empty colors fall back to the colors that the built-in
+// templates of the respective prompt use, for example red for errors, or leave
+// the text uncolored if the prompt has no such color. HelpColor colors hints,
+// status messages and other help texts that are rendered next to the actual
+// content. If it is empty, help texts are rendered faint.
+type Theme struct {
+	PromptColor     string
+	SelectedColor   string
+	UnselectedColor string
+	SuccessColor    string
+	ErrorColor      string
+	HelpColor       string
+}
+
+// WithDefaults returns a copy of the theme in which all empty colors are
+// replaced with the corresponding colors of defaults. The prompts use it to
+// fill in the colors of their built-in templates.
+func (t Theme) WithDefaults(defaults Theme) Theme {
+	orDefault := func(color string, defaultColor string) string {
+		if color == "" {
+			return defaultColor
+		}
+
+		return color
+	}
+
+	return Theme{
+		PromptColor:     orDefault(t.PromptColor, defaults.PromptColor),
+		SelectedColor:   orDefault(t.SelectedColor, defaults.SelectedColor),
+		UnselectedColor: orDefault(t.UnselectedColor, defaults.UnselectedColor),
+		SuccessColor:    orDefault(t.SuccessColor, defaults.SuccessColor),
+		ErrorColor:      orDefault(t.ErrorColor, defaults.ErrorColor),
+		HelpColor:       orDefault(t.HelpColor, defaults.HelpColor),
+	}
+}
+
+// TemplateFuncs returns the template functions ThemePrompt, ThemeSelected,
+// ThemeUnselected, ThemeSuccess, ThemeError and ThemeHelp which color a string
+// with the corresponding color of the theme using the given color profile.
+// They can be merged into the ExtendedTemplateFuncs of any prompt to use the
+// theme in custom templates.
+func (t Theme) TemplateFuncs(profile termenv.Profile) template.FuncMap {
+	colorize := func(color string) func(string) string {
+		return func(s string) string {
+			return profile.String(s).Foreground(profile.Color(color)).String()
+		}
+	}
+
+	return template.FuncMap{
+		"ThemePrompt":     colorize(t.PromptColor),
+		"ThemeSelected":   colorize(t.SelectedColor),
+		"ThemeUnselected": colorize(t.UnselectedColor),
+		"ThemeSuccess":    colorize(t.SuccessColor),
+		"ThemeError":      colorize(t.ErrorColor),
+		"ThemeHelp": func(s string) string {
+			if t.HelpColor == "" {
+				return profile.String(s).Faint().String()
+			}
+
+			return colorize(t.HelpColor)(s)
+		},
+	}
+}
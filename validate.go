@@ -0,0 +1,49 @@
+package promptkit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfigValidator is implemented by all prompts of promptkit. ValidateConfig
+// checks the configuration of a prompt such as its key map and templates
+// without running it.
+type ConfigValidator interface {
+	ValidateConfig() error
+}
+
+// ValidationErrors holds the errors of all prompts that failed validation in
+// ValidateAll. Each error identifies the prompt it belongs to.
+type ValidationErrors []error
+
+// Error returns all validation errors on separate lines.
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// ValidateAll validates all prompts such that misconfigured prompts can be
+// detected once at program start or in tests instead of when they are run. If
+// one or more prompts are invalid, a ValidationErrors is returned that
+// identifies each invalid prompt by its position in the arguments and its
+// type.
+func ValidateAll(prompts ...ConfigValidator) error {
+	var errs ValidationErrors
+
+	for i, prompt := range prompts {
+		err := prompt.ValidateConfig()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("prompt %d (%T): %w", i, prompt, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}